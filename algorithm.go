@@ -7,6 +7,10 @@ import (
 )
 
 // Algorithm is an algorithm for both signing and verifying a JWT.
+//
+// The algorithms created by this package hold no state other than their keys, which are never
+// modified, so a single instance is safe for concurrent use by multiple goroutines.
+// Resolvers are the exception, since they keep the algorithm resolved for a token.
 type Algorithm interface {
 	Name() string
 	Sign(headerPayload []byte) ([]byte, error)
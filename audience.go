@@ -1,6 +1,14 @@
 package jwt
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// ErrAudienceInvalid is the error for when an "aud" claim is neither a string nor an array of strings.
+var ErrAudienceInvalid = internal.NewError("jwt: aud claim must be a string or an array of strings")
 
 // Audience is a special claim that may either be
 // a single string or an array of strings, as per the RFC 7519.
@@ -19,6 +27,7 @@ func (a Audience) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON implements an unmarshaling function for "aud" claim.
+// A single string is unmarshaled into a single-element Audience.
 func (a *Audience) UnmarshalJSON(b []byte) error {
 	var (
 		v   interface{}
@@ -28,6 +37,8 @@ func (a *Audience) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	switch vv := v.(type) {
+	case nil:
+		return nil
 	case string:
 		aud := make(Audience, 1)
 		aud[0] = vv
@@ -35,9 +46,136 @@ func (a *Audience) UnmarshalJSON(b []byte) error {
 	case []interface{}:
 		aud := make(Audience, len(vv))
 		for i := range vv {
-			aud[i] = vv[i].(string)
+			s, ok := vv[i].(string)
+			if !ok {
+				return internal.Errorf("jwt: element %d is %v: %w", i, vv[i], ErrAudienceInvalid)
+			}
+			aud[i] = s
 		}
 		*a = aud
+	default:
+		return internal.Errorf("jwt: got %s: %w", b, ErrAudienceInvalid)
 	}
 	return nil
 }
+
+// Normalize returns a copy of the Audience without empty strings and duplicates,
+// preserving the order in which audiences first appear.
+func (a Audience) Normalize() Audience {
+	if a == nil {
+		return nil
+	}
+	norm := make(Audience, 0, len(a))
+	for _, aud := range a {
+		if aud != "" && !norm.Contains(aud) {
+			norm = append(norm, aud)
+		}
+	}
+	return norm
+}
+
+// Contains reports whether s is one of the audiences.
+func (a Audience) Contains(s string) bool {
+	for _, aud := range a {
+		if aud == s {
+			return true
+		}
+	}
+	return false
+}
+
+// AudienceAsArray wraps payload so its "aud" claim is always marshaled as an array of strings,
+// even when it has a single audience, since some consumers don't support the single string form.
+// Only the token signed with the returned payload is affected:
+//
+//	token, err := jwt.Sign(jwt.AudienceAsArray(pl), alg)
+func AudienceAsArray(payload interface{}) interface{} {
+	ap := wrapAudience(payload)
+	ap.asArray = true
+	return ap
+}
+
+// NormalizeAudience wraps payload so its "aud" claim is normalized with Normalize before being marshaled,
+// so issued tokens don't carry empty or duplicate audiences. It can be combined with AudienceAsArray.
+func NormalizeAudience(payload interface{}) interface{} {
+	ap := wrapAudience(payload)
+	ap.normalize = true
+	return ap
+}
+
+// audiencePayload marshals a payload and rewrites its "aud" claim.
+type audiencePayload struct {
+	payload   interface{}
+	asArray   bool
+	normalize bool
+}
+
+func wrapAudience(payload interface{}) audiencePayload {
+	if ap, ok := payload.(audiencePayload); ok {
+		return ap
+	}
+	return audiencePayload{payload: payload}
+}
+
+// MarshalJSON marshals the wrapped payload, including its private claims, and rewrites its "aud" claim.
+func (ap audiencePayload) MarshalJSON() ([]byte, error) {
+	pb, err := marshalPayload(ap.payload)
+	if err != nil {
+		return nil, err
+	}
+	return replaceMember(pb, "aud", func(raw json.RawMessage) ([]byte, error) {
+		var aud Audience
+		if err := JSONUnmarshal(raw, &aud); err != nil {
+			return nil, err
+		}
+		if ap.normalize {
+			aud = aud.Normalize()
+		}
+		if ap.asArray {
+			if aud == nil {
+				aud = Audience{} // avoid marshaling to null
+			}
+			return JSONMarshal([]string(aud))
+		}
+		return aud.MarshalJSON()
+	})
+}
+
+// replaceMember replaces the value of the member called name in a marshaled JSON object with
+// the one returned by fn, keeping the order of all members.
+func replaceMember(obj []byte, name string, fn func(json.RawMessage) ([]byte, error)) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(obj))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(obj)+2))
+	buf.WriteByte('{')
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var raw json.RawMessage
+		if err = dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		if key == name {
+			if raw, err = fn(raw); err != nil {
+				return nil, err
+			}
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		kb, err := JSONMarshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(raw)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
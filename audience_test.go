@@ -2,9 +2,11 @@ package jwt_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -78,6 +80,143 @@ func TestAudienceUnmarshal(t *testing.T) {
 	}
 }
 
+func TestAudienceAsArray(t *testing.T) {
+	testCases := []struct {
+		payload  interface{}
+		expected string
+	}{
+		{jwt.Payload{Issuer: "foo", Audience: jwt.Audience{"foo"}, JWTID: "bar"}, `{"iss":"foo","aud":["foo"],"jti":"bar"}`},
+		{&jwt.Payload{Audience: jwt.Audience{"foo", "bar"}}, `{"aud":["foo","bar"]}`},
+		{jwt.Payload{Subject: "foo"}, `{"sub":"foo"}`},
+		{
+			jwt.Payload{Audience: jwt.Audience{"foo"}, PrivateClaims: map[string]json.RawMessage{"zzz": json.RawMessage(`true`)}},
+			`{"aud":["foo"],"zzz":true}`,
+		},
+		{testPayload{Payload: jwt.Payload{Audience: jwt.Audience{"foo"}}, String: "bar"}, `{"aud":["foo"],"string":"bar"}`},
+		{nil, `{}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			b, err := json.Marshal(jwt.AudienceAsArray(tc.payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkAudMarshal(t, b, tc.expected)
+		})
+	}
+	t.Run("only wrapped payload", func(t *testing.T) {
+		pl := jwt.Payload{Audience: jwt.Audience{"foo"}}
+		if _, err := json.Marshal(jwt.AudienceAsArray(pl)); err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(pl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkAudMarshal(t, b, `{"aud":"foo"}`)
+	})
+	t.Run("sign", func(t *testing.T) {
+		alg := jwt.NewHS256(hmacKey1)
+		token, err := jwt.Sign(jwt.AudienceAsArray(jwt.Payload{Audience: jwt.Audience{"foo"}}), alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rt, err := jwt.Decode(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkAudMarshal(t, rt.Payload(), `{"aud":["foo"]}`)
+		var pl jwt.Payload
+		if _, err = jwt.Verify(token, alg, &pl); err != nil {
+			t.Fatal(err)
+		}
+		checkAudUnmarshal(t, pl.Audience, jwt.Audience{"foo"})
+	})
+}
+
+func TestAudienceNormalize(t *testing.T) {
+	testCases := []struct {
+		aud  jwt.Audience
+		want jwt.Audience
+	}{
+		{jwt.Audience{"foo", "", "bar", "foo", "baz", "bar"}, jwt.Audience{"foo", "bar", "baz"}},
+		{jwt.Audience{""}, jwt.Audience{}},
+		{nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			orig := append(jwt.Audience(nil), tc.aud...)
+			if want, got := tc.want, tc.aud.Normalize(); !cmp.Equal(got, want) {
+				t.Errorf("jwt.Audience.Normalize mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := orig, tc.aud; !cmp.Equal(got, want) {
+				t.Errorf("jwt.Audience.Normalize modified the receiver (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestNormalizeAudience(t *testing.T) {
+	testCases := []struct {
+		payload  interface{}
+		expected string
+	}{
+		{jwt.NormalizeAudience(jwt.Payload{Audience: jwt.Audience{"foo", "foo"}}), `{"aud":"foo"}`},
+		{jwt.NormalizeAudience(jwt.Payload{Audience: jwt.Audience{"foo", "", "bar", "foo"}}), `{"aud":["foo","bar"]}`},
+		{jwt.NormalizeAudience(jwt.Payload{Audience: jwt.Audience{""}}), `{"aud":""}`},
+		{jwt.AudienceAsArray(jwt.NormalizeAudience(jwt.Payload{Audience: jwt.Audience{"foo", "foo"}})), `{"aud":["foo"]}`},
+		{jwt.NormalizeAudience(jwt.AudienceAsArray(jwt.Payload{Audience: jwt.Audience{"", "foo"}})), `{"aud":["foo"]}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			b, err := json.Marshal(tc.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkAudMarshal(t, b, tc.expected)
+		})
+	}
+}
+
+func TestAudienceContains(t *testing.T) {
+	aud := jwt.Audience{"foo", "bar"}
+	for s, want := range map[string]bool{"foo": true, "bar": true, "baz": false, "": false} {
+		if got := aud.Contains(s); got != want {
+			t.Errorf("jwt.Audience.Contains(%q) mismatch (-want +got):\n%s", s, cmp.Diff(want, got))
+		}
+	}
+}
+
+func TestAudienceUnmarshalInvalid(t *testing.T) {
+	testCases := []struct {
+		jstr []byte
+		err  error
+	}{
+		{[]byte(`null`), nil},
+		{[]byte(`["foo",1]`), jwt.ErrAudienceInvalid},
+		{[]byte(`[null]`), jwt.ErrAudienceInvalid},
+		{[]byte(`1337`), jwt.ErrAudienceInvalid},
+		{[]byte(`true`), jwt.ErrAudienceInvalid},
+		{[]byte(`{"foo":"bar"}`), jwt.ErrAudienceInvalid},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.jstr), func(t *testing.T) {
+			var aud jwt.Audience
+			if want, got := tc.err, aud.UnmarshalJSON(tc.jstr); !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Audience.Unmarshal err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestAudienceUnmarshalInvalidElement(t *testing.T) {
+	var aud jwt.Audience
+	err := aud.UnmarshalJSON([]byte(`["foo",1337]`))
+	if want, got := "jwt: element 1 is 1337: jwt: aud claim must be a string or an array of strings", fmt.Sprint(err); got != want {
+		t.Errorf("jwt.Audience.Unmarshal err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
 func checkAudMarshal(t *testing.T, got []byte, want string) {
 	if string(got) != want {
 		t.Errorf("jwt.Audience.Marshal mismatch (-want +got):\n%s", cmp.Diff(want, got))
@@ -1,6 +1,9 @@
 package jwt_test
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"testing"
 	"time"
 
@@ -78,3 +81,54 @@ func BenchmarkVerify(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkHMACSHA(b *testing.B) {
+	headerPayload := []byte(
+		"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9." +
+			"eyJpc3MiOiJnYnJsc25jaHMiLCJzdWIiOiJzb21lb25lIiwiYXVkIjpbImh0dHBzOi8vZ29sYW5nLm9yZyIsImh0dHBzOi8vand0LmlvIl0sImV4cCI6MTU5MzM5MTE4MiwibmJmIjoxNTYyMjg4OTgyLCJpYXQiOjE1NjIyODcxODIsImp0aSI6ImZvb2JhciJ9",
+	)
+	b.Run("Pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			sig, err := benchHS256.Sign(headerPayload)
+			if err != nil {
+				b.Fatal(err)
+			}
+			benchRecv = sig
+		}
+	})
+	b.Run("Unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			hh := hmac.New(sha256.New, []byte("secret"))
+			if _, err := hh.Write(headerPayload); err != nil {
+				b.Fatal(err)
+			}
+			benchRecv = hh.Sum(nil)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		sig, err := benchHS256.Sign(headerPayload)
+		if err != nil {
+			b.Fatal(err)
+		}
+		sig64 := []byte(base64.RawURLEncoding.EncodeToString(sig))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			if err := benchHS256.Verify(headerPayload, sig64); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := benchHS256.Sign(headerPayload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}
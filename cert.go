@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// ErrCertInvalid is the error for a certificate that can't be parsed or verified.
+var ErrCertInvalid = internal.NewError("jwt: certificate is invalid")
+
+// NewRSAVerifierFromCert creates an algorithm called alg, such as "RS256" or "PS256", for verifying
+// signatures with the RSA public key of a PEM encoded X.509 certificate.
+//
+// If opts is not nil, the certificate is verified with it, which checks that it's not expired
+// at opts.CurrentTime, or now if zero, and that it chains to opts.Roots, or the system roots if nil.
+// Since certificates are checked for server authentication by default, opts.KeyUsages may need
+// to be set, for example, to x509.ExtKeyUsageAny.
+func NewRSAVerifierFromCert(pemData []byte, alg string, opts *x509.VerifyOptions) (Algorithm, error) {
+	cert, err := parseCertPEM(pemData, opts)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, internal.Errorf("jwt: got %T, want RSA: %w", cert.PublicKey, ErrPEMKeyType)
+	}
+	newAlg, ok := AlgorithmByName(alg)
+	if !ok {
+		return nil, internal.Errorf("jwt: %q algorithm: %w", alg, ErrUnsupportedKey)
+	}
+	return newAlg(pub)
+}
+
+// NewECVerifierFromCert is like NewRSAVerifierFromCert, but for certificates with elliptic curve
+// public keys, whose algorithm is implied by the curve, as in "ES256" for P-256.
+func NewECVerifierFromCert(pemData []byte, opts *x509.VerifyOptions) (Algorithm, error) {
+	cert, err := parseCertPEM(pemData, opts)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, internal.Errorf("jwt: got %T, want ECDSA: %w", cert.PublicKey, ErrPEMKeyType)
+	}
+	alg := map[string]string{"P-256": "ES256", "P-384": "ES384", "P-521": "ES512"}[curveNames[pub.Curve]]
+	newAlg, ok := AlgorithmByName(alg)
+	if !ok {
+		return nil, internal.Errorf("jwt: %q curve: %w", pub.Params().Name, ErrUnsupportedKey)
+	}
+	return newAlg(pub)
+}
+
+// parseCertPEM parses the first PEM block of data as a certificate and verifies it with opts, if not nil.
+func parseCertPEM(data []byte, opts *x509.VerifyOptions) (*x509.Certificate, error) {
+	block, err := decodePEM(data)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil, internal.Errorf("jwt: %q block: %w", block.Type, ErrPEMKeyType)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, internal.Errorf("jwt: %v: %w", err, ErrCertInvalid)
+	}
+	if opts != nil {
+		if _, err = cert.Verify(*opts); err != nil {
+			return nil, internal.Errorf("jwt: %v: %w", err, ErrCertInvalid)
+		}
+	}
+	return cert, nil
+}
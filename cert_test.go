@@ -0,0 +1,144 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+var certNow = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// newCert creates a PEM encoded certificate for pub, valid for a day from certNow,
+// signed by parent's key priv, or self-signed if parent is nil.
+func newCert(t *testing.T, pub crypto.PublicKey, parent *x509.Certificate, priv crypto.Signer, isCA bool) (*x509.Certificate, []byte) {
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             certNow,
+		NotAfter:              certNow.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if parent == nil {
+		parent = tmpl
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestVerifierFromCert(t *testing.T) {
+	ca, _ := newCert(t, es384PublicKey1, nil, es384PrivateKey1, true)
+	_, rsaCert := newCert(t, rsaPublicKey1, ca, es384PrivateKey1, false)
+	_, ecCert := newCert(t, es256PublicKey1, ca, es384PrivateKey1, false)
+	otherCA, _ := newCert(t, es384PublicKey2, nil, es384PrivateKey2, true)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherCA)
+	verifyOpts := func(roots *x509.CertPool, now time.Time) *x509.VerifyOptions {
+		return &x509.VerifyOptions{Roots: roots, CurrentTime: now, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}
+	}
+
+	testCases := []struct {
+		name   string
+		newAlg func(opts *x509.VerifyOptions) (jwt.Algorithm, error)
+		signer jwt.Algorithm
+		opts   *x509.VerifyOptions
+		err    error
+	}{
+		{
+			"RSA",
+			func(opts *x509.VerifyOptions) (jwt.Algorithm, error) {
+				return jwt.NewRSAVerifierFromCert(rsaCert, "PS256", opts)
+			},
+			jwt.NewPS256(jwt.RSAPrivateKey(rsaPrivateKey1)),
+			nil,
+			nil,
+		},
+		{
+			"RSA with chain",
+			func(opts *x509.VerifyOptions) (jwt.Algorithm, error) {
+				return jwt.NewRSAVerifierFromCert(rsaCert, "RS256", opts)
+			},
+			jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey1)),
+			verifyOpts(roots, certNow.Add(time.Hour)),
+			nil,
+		},
+		{
+			"RSA with non RSA algorithm",
+			func(opts *x509.VerifyOptions) (jwt.Algorithm, error) {
+				return jwt.NewRSAVerifierFromCert(rsaCert, "HS256", opts)
+			},
+			nil,
+			nil,
+			jwt.ErrUnsupportedKey,
+		},
+		{
+			"EC as RSA",
+			func(opts *x509.VerifyOptions) (jwt.Algorithm, error) {
+				return jwt.NewRSAVerifierFromCert(ecCert, "RS256", opts)
+			},
+			nil,
+			nil,
+			jwt.ErrPEMKeyType,
+		},
+		{
+			"EC",
+			func(opts *x509.VerifyOptions) (jwt.Algorithm, error) { return jwt.NewECVerifierFromCert(ecCert, opts) },
+			jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1)),
+			verifyOpts(roots, certNow.Add(time.Hour)),
+			nil,
+		},
+		{
+			"expired",
+			func(opts *x509.VerifyOptions) (jwt.Algorithm, error) { return jwt.NewECVerifierFromCert(ecCert, opts) },
+			nil,
+			verifyOpts(roots, certNow.Add(48*time.Hour)),
+			jwt.ErrCertInvalid,
+		},
+		{
+			"unknown authority",
+			func(opts *x509.VerifyOptions) (jwt.Algorithm, error) { return jwt.NewECVerifierFromCert(ecCert, opts) },
+			nil,
+			verifyOpts(otherRoots, certNow.Add(time.Hour)),
+			jwt.ErrCertInvalid,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			alg, err := tc.newAlg(tc.opts)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if err != nil {
+				return
+			}
+			token, err := jwt.Sign(tp, tc.signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pl testPayload
+			if _, err = jwt.Verify(token, alg, &pl); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package jwt_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+)
+
+// TestConcurrentUse shares algorithms between goroutines, so it's meant to be run with -race.
+func TestConcurrentUse(t *testing.T) {
+	algs := []jwt.Algorithm{
+		jwt.NewHS256(hmacKey1),
+		jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey1)),
+		jwt.NewPS256(jwt.RSAPrivateKey(rsaPrivateKey1)),
+		jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1)),
+		jwt.NewEd25519(jwt.Ed25519PrivateKey(ed25519PrivateKey1)),
+		jwt.NewKeySet(jwt.NewHS256(hmacKey2), jwt.NewHS256(hmacKey1)),
+	}
+	const goroutines = 8
+	for _, alg := range algs {
+		alg := alg
+		t.Run(alg.Name(), func(t *testing.T) {
+			token, err := jwt.Sign(tp, alg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var wg sync.WaitGroup
+			errs := make(chan error, 2*goroutines)
+			for i := 0; i < goroutines; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := jwt.Sign(tp, alg); err != nil {
+						errs <- err
+					}
+					var pl testPayload
+					if _, err := jwt.Verify(token, alg, &pl); err != nil {
+						errs <- err
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Error(err)
+			}
+		})
+	}
+}
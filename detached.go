@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// SignDetached signs payload with alg without encoding it and without including it in the token,
+// as per the RFC 7797. The payload must then be sent along with the token by other means.
+//
+// The "b64" header parameter is set to false and listed in the "crit" header parameter.
+func SignDetached(payload []byte, alg Algorithm, opts ...SignOption) ([]byte, error) {
+	hd, err := signHeader(alg, opts)
+	if err != nil {
+		return nil, err
+	}
+	b64 := false
+	hd.Base64 = &b64
+	if !contains(hd.Critical, "b64") {
+		hd.Critical = append(hd.Critical, "b64")
+	}
+	hb, err := marshalHeader(hd)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := base64.RawURLEncoding
+	h64len := enc.EncodedLen(len(hb))
+	input := make([]byte, h64len+1+len(payload))
+	enc.Encode(input, hb)
+	input[h64len] = '.'
+	copy(input[h64len+1:], payload)
+	sig, err := alg.Sign(input)
+	if err != nil {
+		return nil, err
+	}
+	token := make([]byte, h64len+2+enc.EncodedLen(len(sig)))
+	copy(token, input[:h64len])
+	token[h64len], token[h64len+1] = '.', '.'
+	enc.Encode(token[h64len+2:], sig)
+	return token, nil
+}
+
+// VerifyDetached verifies a token whose payload has been detached from it, as per the RFC 7797.
+// The payload part of the token must be empty, and payload is used in its place.
+// If the "b64" header parameter is false, payload is verified as is, otherwise it's Base64URL encoded first.
+//
+// Other than that, verification works like Verify. In particular, if validators are passed
+// with ValidatePayload or ValidateRawPayload, payload is decoded as JSON, into the Payload passed
+// to ValidatePayload, after the signature is verified and they're run against it,
+// so it must then be a JSON object.
+func VerifyDetached(token, payload []byte, alg Algorithm, opts ...VerifyOption) (Header, error) {
+	opts = append([]VerifyOption{CriticalParams("b64"), detachedPayload(payload)}, opts...)
+	rt, err := verifySignature(context.Background(), token, alg, opts)
+	if err != nil || len(rt.vds) == 0 && len(rt.rawVds) == 0 {
+		return rt.hd, err
+	}
+	pl := rt.pl
+	if pl == nil {
+		pl = new(Payload)
+	}
+	return rt.hd, rt.unmarshal(payload, pl)
+}
+
+func detachedPayload(payload []byte) VerifyOption {
+	return func(rt *RawToken) error {
+		if len(rt.payload()) > 0 {
+			return internal.Errorf("jwt: payload is not detached: %w", ErrMalformed)
+		}
+		if b64 := rt.hd.Base64; b64 != nil && !*b64 && !contains(rt.hd.Critical, "b64") {
+			return internal.Errorf(`jwt: "b64" is not critical: %w`, ErrCritValidation)
+		}
+		rt.detached = payload
+		rt.isDetached = true
+		return nil
+	}
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetached(t *testing.T) {
+	var (
+		alg     = jwt.NewHS256(hmacKey1)
+		payload = []byte("$.02")
+		f       = false
+	)
+	token, err := jwt.SignDetached(payload, alg, jwt.KeyID("detached"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	attached, err := jwt.Sign(tp, alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		token   []byte
+		payload []byte
+		alg     jwt.Algorithm
+		want    jwt.Header
+		err     error
+	}{
+		{
+			name:    "valid",
+			token:   token,
+			payload: payload,
+			alg:     alg,
+			want: jwt.Header{
+				Algorithm: "HS256",
+				KeyID:     "detached",
+				Type:      "JWT",
+				Critical:  []string{"b64"},
+				Base64:    &f,
+			},
+		},
+		{
+			name:    "tampered payload",
+			token:   token,
+			payload: []byte("$.03"),
+			alg:     alg,
+			err:     jwt.ErrHMACVerification,
+		},
+		{
+			name:    "wrong key",
+			token:   token,
+			payload: payload,
+			alg:     jwt.NewHS256(hmacKey2),
+			err:     jwt.ErrHMACVerification,
+		},
+		{
+			name:    "attached payload",
+			token:   attached,
+			payload: payload,
+			alg:     alg,
+			err:     jwt.ErrMalformed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hd, err := jwt.VerifyDetached(tc.token, tc.payload, tc.alg)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.VerifyDetached err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if err != nil {
+				return
+			}
+			if want, got := tc.want, hd; !cmp.Equal(got, want) {
+				t.Errorf("jwt.VerifyDetached mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestDetachedValidators(t *testing.T) {
+	alg := jwt.NewHS256(hmacKey1)
+	payload := []byte(`{"iss":"iss","sub":"sub"}`)
+	token, err := jwt.SignDetached(payload, alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name    string
+		payload []byte
+		vd      jwt.Validator
+		err     error
+	}{
+		{"valid", payload, jwt.IssuerValidator("iss"), nil},
+		{"invalid claim", payload, jwt.IssuerValidator("other"), jwt.ErrIssValidation},
+		{"missing exp", payload, jwt.ExpirationTimeValidator(time.Now()), jwt.ErrExpValidation},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pl jwt.Payload
+			_, err := jwt.VerifyDetached(token, tc.payload, alg, jwt.ValidatePayload(&pl, tc.vd))
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.VerifyDetached err = %v, want %v", got, want)
+			}
+			if want, got := "sub", pl.Subject; got != want {
+				t.Errorf("jwt.Payload.Subject mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+
+	t.Run("not JSON", func(t *testing.T) {
+		token, err := jwt.SignDetached([]byte("$.02"), alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pl jwt.Payload
+		_, err = jwt.VerifyDetached(token, []byte("$.02"), alg, jwt.ValidatePayload(&pl, jwt.IssuerValidator("iss")))
+		if want, got := jwt.ErrMalformed, err; !internal.ErrorIs(got, want) {
+			t.Errorf("jwt.VerifyDetached err = %v, want %v", got, want)
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/rand"
+	"encoding/asn1"
+	"io"
 	"math/big"
 
 	"github.com/gbrlsnchs/jwt/v3/internal"
@@ -15,7 +17,9 @@ var (
 	// ErrECDSANilPubKey is the error for trying to verify a JWT with a nil public key.
 	ErrECDSANilPubKey = internal.NewError("jwt: ECDSA public key is nil")
 	// ErrECDSAVerification is the error for an invalid ECDSA signature.
-	ErrECDSAVerification = internal.NewError("jwt: ECDSA verification failed")
+	ErrECDSAVerification = internal.WrapError("jwt: ECDSA verification failed", ErrSignatureMismatch)
+	// ErrECDSAInvalidCurve is the error for trying to use a key whose curve doesn't match the algorithm.
+	ErrECDSAInvalidCurve = internal.NewError("jwt: ECDSA key has an invalid curve")
 
 	_ Algorithm = new(ECDSASHA)
 )
@@ -34,6 +38,29 @@ func ECDSAPublicKey(pub *ecdsa.PublicKey) func(*ECDSASHA) {
 	}
 }
 
+// ECDSAAcceptASN1 is an option for also accepting ASN.1 DER encoded signatures when verifying,
+// which some non-conformant libraries create instead of the R || S concatenation required by the RFC 7518.
+// Signing always creates conformant signatures.
+func ECDSAAcceptASN1() func(*ECDSASHA) {
+	return func(es *ECDSASHA) {
+		es.acceptASN1 = true
+	}
+}
+
+// ECDSARandom is an option to set the source of randomness used when signing, which defaults to
+// crypto/rand.Reader. It's meant for tests needing reproducible signatures, for example, with golden files.
+//
+// WARNING: signing with a predictable source leaks the private key, so it must never be used in production.
+//
+// Depending on the Go version, crypto/ecdsa mixes other randomness into signatures or ignores r,
+// as Go 1.26 and later do unless GODEBUG=cryptocustomrand=1 is set, so signatures aren't guaranteed
+// to be reproducible. Ed25519 signatures, on the other hand, are always deterministic.
+func ECDSARandom(r io.Reader) func(*ECDSASHA) {
+	return func(es *ECDSASHA) {
+		es.rand = r
+	}
+}
+
 func byteSize(bitSize int) int {
 	byteSize := bitSize / 8
 	if bitSize%8 > 0 {
@@ -50,13 +77,17 @@ type ECDSASHA struct {
 	sha  crypto.Hash
 	size int
 
+	acceptASN1 bool
+	rand       io.Reader
+
 	pool *hashPool
 }
 
-func newECDSASHA(name string, opts []func(*ECDSASHA), sha crypto.Hash) *ECDSASHA {
+func newECDSASHA(name string, opts []func(*ECDSASHA), sha crypto.Hash, curve string) *ECDSASHA {
 	es := ECDSASHA{
 		name: name,
 		sha:  sha,
+		rand: rand.Reader,
 		pool: newHashPool(sha.New),
 	}
 	for _, opt := range opts {
@@ -70,23 +101,37 @@ func newECDSASHA(name string, opts []func(*ECDSASHA), sha crypto.Hash) *ECDSASHA
 		}
 		es.pub = &es.priv.PublicKey
 	}
+	if curve != "" && es.pub.Params().Name != curve {
+		panic(internal.Errorf("jwt: got %q, want %q: %w", es.pub.Params().Name, curve, ErrECDSAInvalidCurve))
+	}
 	es.size = byteSize(es.pub.Params().BitSize) * 2
 	return &es
 }
 
 // NewES256 creates a new algorithm using ECDSA and SHA-256.
 func NewES256(opts ...func(*ECDSASHA)) *ECDSASHA {
-	return newECDSASHA("ES256", opts, crypto.SHA256)
+	return newECDSASHA("ES256", opts, crypto.SHA256, "")
 }
 
 // NewES384 creates a new algorithm using ECDSA and SHA-384.
 func NewES384(opts ...func(*ECDSASHA)) *ECDSASHA {
-	return newECDSASHA("ES384", opts, crypto.SHA384)
+	return newECDSASHA("ES384", opts, crypto.SHA384, "")
 }
 
 // NewES512 creates a new algorithm using ECDSA and SHA-512.
 func NewES512(opts ...func(*ECDSASHA)) *ECDSASHA {
-	return newECDSASHA("ES512", opts, crypto.SHA512)
+	return newECDSASHA("ES512", opts, crypto.SHA512, "")
+}
+
+// NewES256K creates a new algorithm using ECDSA over the secp256k1 curve and SHA-256, as per the RFC 8812.
+//
+// Since the standard library doesn't implement secp256k1, keys must use an elliptic.Curve
+// implementation from a third-party package whose parameters are named "secp256k1".
+// Keys on other curves make it panic with ErrECDSAInvalidCurve.
+//
+// Signatures are randomized rather than deterministic, like the ones created by the other ECDSA algorithms.
+func NewES256K(opts ...func(*ECDSASHA)) *ECDSASHA {
+	return newECDSASHA("ES256K", opts, crypto.SHA256, "secp256k1")
 }
 
 // Name returns the algorithm's name.
@@ -108,6 +153,7 @@ func (es *ECDSASHA) Size() int {
 }
 
 // Verify verifies a signature based on headerPayload using ECDSA-SHA.
+// Only signatures in the R || S form are accepted, unless the ECDSAAcceptASN1 option is set.
 func (es *ECDSASHA) Verify(headerPayload, sig []byte) (err error) {
 	if es.pub == nil {
 		return ErrECDSANilPubKey
@@ -115,21 +161,25 @@ func (es *ECDSASHA) Verify(headerPayload, sig []byte) (err error) {
 	if sig, err = internal.DecodeToBytes(sig); err != nil {
 		return err
 	}
-	byteSize := byteSize(es.pub.Params().BitSize)
-	if len(sig) != byteSize*2 {
-		return ErrECDSAVerification
-	}
-
-	r := big.NewInt(0).SetBytes(sig[:byteSize])
-	s := big.NewInt(0).SetBytes(sig[byteSize:])
 	sum, err := es.pool.sign(headerPayload)
 	if err != nil {
 		return err
 	}
-	if !ecdsa.Verify(es.pub, sum, r, s) {
-		return ErrECDSAVerification
+	if byteSize := byteSize(es.pub.Params().BitSize); len(sig) == byteSize*2 {
+		r := big.NewInt(0).SetBytes(sig[:byteSize])
+		s := big.NewInt(0).SetBytes(sig[byteSize:])
+		if ecdsa.Verify(es.pub, sum, r, s) {
+			return nil
+		}
+	}
+	if es.acceptASN1 {
+		var rs struct{ R, S *big.Int }
+		if rest, err := asn1.Unmarshal(sig, &rs); err == nil && len(rest) == 0 &&
+			rs.R.Sign() > 0 && rs.S.Sign() > 0 && ecdsa.Verify(es.pub, sum, rs.R, rs.S) {
+			return nil
+		}
 	}
-	return nil
+	return ErrECDSAVerification
 }
 
 func (es *ECDSASHA) sign(headerPayload []byte) ([]byte, error) {
@@ -137,7 +187,7 @@ func (es *ECDSASHA) sign(headerPayload []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	r, s, err := ecdsa.Sign(rand.Reader, es.priv, sum)
+	r, s, err := ecdsa.Sign(es.rand, es.priv, sum)
 	if err != nil {
 		return nil, err
 	}
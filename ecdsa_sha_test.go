@@ -1,10 +1,14 @@
 package jwt_test
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
-
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"math/big"
 	"testing"
 
 	"github.com/gbrlsnchs/jwt/v3"
@@ -22,6 +26,9 @@ var (
 	es512PrivateKey1, es512PublicKey1 = genECDSAKeys(elliptic.P521())
 	es512PrivateKey2, es512PublicKey2 = genECDSAKeys(elliptic.P521())
 
+	es256kPrivateKey1, es256kPublicKey1 = genECDSAKeys(secp256k1)
+	es256kPrivateKey2, es256kPublicKey2 = genECDSAKeys(secp256k1)
+
 	ecdsaTestCases = []testCase{
 		{
 			alg:       jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1)),
@@ -45,7 +52,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrECDSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1)),
@@ -105,7 +112,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrECDSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewES384(jwt.ECDSAPrivateKey(es384PrivateKey1)),
@@ -165,7 +172,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrECDSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewES512(jwt.ECDSAPrivateKey(es512PrivateKey1)),
@@ -203,6 +210,42 @@ var (
 			signErr:     nil,
 			verifyErr:   jwt.ErrECDSAVerification,
 		},
+		{
+			alg:       jwt.NewES256K(jwt.ECDSAPrivateKey(es256kPrivateKey1)),
+			payload:   tp,
+			verifyAlg: jwt.NewES256K(jwt.ECDSAPublicKey(es256kPublicKey1)),
+			wantHeader: jwt.Header{
+				Algorithm: "ES256K",
+				Type:      "JWT",
+			},
+			wantPayload: tp,
+			signErr:     nil,
+			verifyErr:   nil,
+		},
+		{
+			alg:       jwt.NewES256K(jwt.ECDSAPrivateKey(es256kPrivateKey1)),
+			payload:   tp,
+			verifyAlg: jwt.NewES256K(jwt.ECDSAPublicKey(es256kPublicKey2)),
+			wantHeader: jwt.Header{
+				Algorithm: "ES256K",
+				Type:      "JWT",
+			},
+			wantPayload: testPayload{},
+			signErr:     nil,
+			verifyErr:   jwt.ErrECDSAVerification,
+		},
+		{
+			alg:       jwt.NewES256K(jwt.ECDSAPrivateKey(es256kPrivateKey1)),
+			payload:   tp,
+			verifyAlg: jwt.NewES256(jwt.ECDSAPublicKey(es256PublicKey1)),
+			wantHeader: jwt.Header{
+				Algorithm: "ES256K",
+				Type:      "JWT",
+			},
+			wantPayload: testPayload{},
+			signErr:     nil,
+			verifyErr:   jwt.ErrAlgValidation,
+		},
 	}
 )
 
@@ -224,6 +267,10 @@ func TestNewECDSASHA(t *testing.T) {
 		{jwt.NewES512, jwt.ECDSAPrivateKey(nil), jwt.ErrECDSANilPrivKey},
 		{jwt.NewES512, jwt.ECDSAPrivateKey(es512PrivateKey1), nil},
 		{jwt.NewES512, jwt.ECDSAPublicKey(es512PublicKey1), nil},
+		{jwt.NewES256K, nil, jwt.ErrECDSANilPrivKey},
+		{jwt.NewES256K, jwt.ECDSAPrivateKey(es256kPrivateKey1), nil},
+		{jwt.NewES256K, jwt.ECDSAPublicKey(es256kPublicKey1), nil},
+		{jwt.NewES256K, jwt.ECDSAPublicKey(es256PublicKey1), jwt.ErrECDSAInvalidCurve},
 	}
 	for _, tc := range testCases {
 		funcName := funcName(tc.builder)
@@ -247,6 +294,60 @@ func TestNewECDSASHA(t *testing.T) {
 	}
 }
 
+func TestECDSAAcceptASN1(t *testing.T) {
+	testCases := []struct {
+		builder func(...func(*jwt.ECDSASHA)) *jwt.ECDSASHA
+		priv    *ecdsa.PrivateKey
+		pub     *ecdsa.PublicKey
+	}{
+		{jwt.NewES256, es256PrivateKey1, es256PublicKey1},
+		{jwt.NewES384, es384PrivateKey1, es384PublicKey1},
+		{jwt.NewES512, es512PrivateKey1, es512PublicKey1},
+	}
+	for _, tc := range testCases {
+		funcName := funcName(tc.builder)
+		t.Run(funcName, func(t *testing.T) {
+			token, err := jwt.Sign(tp, tc.builder(jwt.ECDSAPrivateKey(tc.priv)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sep := bytes.LastIndexByte(token, '.')
+			sig, err := base64.RawURLEncoding.DecodeString(string(token[sep+1:]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			der, err := asn1.Marshal(struct{ R, S *big.Int }{
+				new(big.Int).SetBytes(sig[:len(sig)/2]),
+				new(big.Int).SetBytes(sig[len(sig)/2:]),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			derToken := append(token[:sep+1:sep+1], base64.RawURLEncoding.EncodeToString(der)...)
+
+			var pl testPayload
+			strict := tc.builder(jwt.ECDSAPublicKey(tc.pub))
+			if _, err = jwt.Verify(token, strict, &pl); err != nil {
+				t.Fatal(err)
+			}
+			_, err = jwt.Verify(derToken, strict, &pl)
+			if want, got := jwt.ErrECDSAVerification, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			lenient := tc.builder(jwt.ECDSAPublicKey(tc.pub), jwt.ECDSAAcceptASN1())
+			for _, token := range [][]byte{token, derToken} {
+				if _, err = jwt.Verify(token, lenient, &pl); err != nil {
+					t.Errorf("jwt.Verify with jwt.ECDSAAcceptASN1: %v", err)
+				}
+			}
+			_, err = jwt.Verify(append(derToken, "AAAA"...), lenient, &pl)
+			if want, got := jwt.ErrECDSAVerification, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
 func genECDSAKeys(c elliptic.Curve) (*ecdsa.PrivateKey, *ecdsa.PublicKey) {
 	priv, err := ecdsa.GenerateKey(c, rand.Reader)
 	if err != nil {
@@ -254,3 +355,121 @@ func genECDSAKeys(c elliptic.Curve) (*ecdsa.PrivateKey, *ecdsa.PublicKey) {
 	}
 	return priv, &priv.PublicKey
 }
+
+// secp256k1 is a naive implementation of the secp256k1 curve, only meant for testing.
+var secp256k1 = func() koblitzCurve {
+	params := &elliptic.CurveParams{Name: "secp256k1", BitSize: 256}
+	params.P, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+	params.N, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+	params.B = big.NewInt(7)
+	params.Gx, _ = new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+	params.Gy, _ = new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+	return koblitzCurve{params}
+}()
+
+type koblitzCurve struct{ *elliptic.CurveParams }
+
+func (c koblitzCurve) Params() *elliptic.CurveParams { return c.CurveParams }
+
+func (c koblitzCurve) IsOnCurve(x, y *big.Int) bool {
+	y2 := new(big.Int).Mul(y, y)
+	x3 := new(big.Int).Mul(x, x)
+	x3.Mul(x3, x).Add(x3, c.B)
+	return y2.Sub(y2, x3).Mod(y2, c.P).Sign() == 0
+}
+
+func (c koblitzCurve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	switch {
+	case x1.Sign() == 0 && y1.Sign() == 0:
+		return new(big.Int).Set(x2), new(big.Int).Set(y2)
+	case x2.Sign() == 0 && y2.Sign() == 0:
+		return new(big.Int).Set(x1), new(big.Int).Set(y1)
+	case x1.Cmp(x2) == 0:
+		if y1.Cmp(y2) == 0 {
+			return c.Double(x1, y1)
+		}
+		return new(big.Int), new(big.Int)
+	}
+	l := new(big.Int).Sub(x2, x1)
+	l.ModInverse(l.Mod(l, c.P), c.P)
+	l.Mul(l, new(big.Int).Sub(y2, y1))
+	return c.point(l, x1, y1, x2)
+}
+
+func (c koblitzCurve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	if y1.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	l := new(big.Int).Lsh(y1, 1)
+	l.ModInverse(l.Mod(l, c.P), c.P)
+	l.Mul(l, new(big.Int).Mul(big.NewInt(3), new(big.Int).Mul(x1, x1)))
+	return c.point(l, x1, y1, x1)
+}
+
+// point returns the point with slope l through (x1, y1) and another point whose X-coordinate is x2.
+func (c koblitzCurve) point(l, x1, y1, x2 *big.Int) (*big.Int, *big.Int) {
+	l.Mod(l, c.P)
+	x3 := new(big.Int).Mul(l, l)
+	x3.Sub(x3, x1).Sub(x3, x2).Mod(x3, c.P)
+	y3 := new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, l).Sub(y3, y1).Mod(y3, c.P)
+	return x3, y3
+}
+
+func (c koblitzCurve) ScalarMult(x1, y1 *big.Int, k []byte) (*big.Int, *big.Int) {
+	x, y := new(big.Int), new(big.Int)
+	for _, b := range k {
+		for i := 7; i >= 0; i-- {
+			x, y = c.Double(x, y)
+			if b>>uint(i)&1 == 1 {
+				x, y = c.Add(x, y, x1, y1)
+			}
+		}
+	}
+	return x, y
+}
+
+func (c koblitzCurve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return c.ScalarMult(c.Gx, c.Gy, k)
+}
+
+func TestECDSARandom(t *testing.T) {
+	// A fixed source may make signatures reproducible, depending on the Go version,
+	// but they must be valid either way.
+	alg := jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1), jwt.ECDSARandom(fixedReader{}))
+	for i := 0; i < 2; i++ {
+		token, err := jwt.Sign(tp, alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pl testPayload
+		if _, err = jwt.Verify(token, jwt.NewES256(jwt.ECDSAPublicKey(es256PublicKey1)), &pl); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestECDSARandomError(t *testing.T) {
+	alg := jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1), jwt.ECDSARandom(errReader{}))
+	_, err := jwt.Sign(tp, alg)
+	if want, got := errRead, err; !internal.ErrorIs(got, want) {
+		t.Errorf("jwt.Sign err = %v, want %v", got, want)
+	}
+}
+
+var errRead = errors.New("read failed")
+
+// errReader is a source of randomness that always fails, for tests only.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+// fixedReader is a predictable source of randomness, for tests only.
+type fixedReader struct{}
+
+func (fixedReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return len(b), nil
+}
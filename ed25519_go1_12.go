@@ -13,7 +13,10 @@ var (
 	// ErrEd25519NilPubKey is the error for trying to verify a JWT with a nil public key.
 	ErrEd25519NilPubKey = internal.NewError("jwt: Ed25519 public key is nil")
 	// ErrEd25519Verification is the error for when verification with Ed25519 fails.
-	ErrEd25519Verification = internal.NewError("jwt: Ed25519 verification failed")
+	ErrEd25519Verification = internal.WrapError("jwt: Ed25519 verification failed", ErrSignatureMismatch)
+	// ErrEd25519KeySize is the error for a key whose size is not the one of Ed25519 keys,
+	// for example, an Ed448 key, which is not supported.
+	ErrEd25519KeySize = internal.NewError("jwt: invalid Ed25519 key size")
 
 	_ Algorithm = new(Ed25519)
 )
@@ -33,12 +36,16 @@ func Ed25519PublicKey(pub ed25519.PublicKey) func(*Ed25519) {
 }
 
 // Ed25519 is an algorithm that uses EdDSA to sign SHA-512 hashes.
+//
+// Out of the curves registered for "EdDSA" by the RFC 8037, only Ed25519 is supported,
+// since Ed448 is implemented neither by the standard library nor by golang.org/x/crypto.
 type Ed25519 struct {
 	priv ed25519.PrivateKey
 	pub  ed25519.PublicKey
 }
 
 // NewEd25519 creates a new algorithm using EdDSA and SHA-512.
+// It panics if a key has the wrong size.
 func NewEd25519(opts ...func(*Ed25519)) *Ed25519 {
 	var ed Ed25519
 	for _, opt := range opts {
@@ -46,18 +53,24 @@ func NewEd25519(opts ...func(*Ed25519)) *Ed25519 {
 			opt(&ed)
 		}
 	}
+	if ed.priv != nil && len(ed.priv) != ed25519.PrivateKeySize {
+		panic(internal.Errorf("jwt: private key has %d bytes: %w", len(ed.priv), ErrEd25519KeySize))
+	}
 	if ed.pub == nil {
 		if len(ed.priv) == 0 {
 			panic(ErrEd25519NilPrivKey)
 		}
 		ed.pub = ed.priv.Public().(ed25519.PublicKey)
 	}
+	if len(ed.pub) != ed25519.PublicKeySize {
+		panic(internal.Errorf("jwt: public key has %d bytes: %w", len(ed.pub), ErrEd25519KeySize))
+	}
 	return &ed
 }
 
-// Name returns the algorithm's name.
+// Name returns the algorithm's name, which is "EdDSA", as per the RFC 8037.
 func (*Ed25519) Name() string {
-	return "Ed25519"
+	return "EdDSA"
 }
 
 // Sign signs headerPayload using the Ed25519 algorithm.
@@ -86,3 +99,16 @@ func (ed *Ed25519) Verify(payload, sig []byte) (err error) {
 	}
 	return nil
 }
+
+func ed25519Factory(key interface{}) (Algorithm, error) {
+	var opt func(*Ed25519)
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		opt = Ed25519PrivateKey(key)
+	case ed25519.PublicKey:
+		opt = Ed25519PublicKey(key)
+	default:
+		return nil, internal.Errorf("jwt: %T: %w", key, ErrUnsupportedKey)
+	}
+	return construct(func() Algorithm { return NewEd25519(opt) })
+}
@@ -0,0 +1,38 @@
+//go:build go1.18
+// +build go1.18
+
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+)
+
+func FuzzVerify(f *testing.F) {
+	alg := jwt.NewHS256(hmacKey1)
+	token, err := jwt.Sign(tp, alg, jwt.KeyID("fuzz"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, seed := range []string{
+		string(token),
+		"",
+		".",
+		"..",
+		"e30..",
+		"e30.e30.",
+		"eyJhbGciOiJIUzI1NiJ9.e30=.AA==",
+		"eyJjcml0IjpbXX0.e30.",
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, token []byte) {
+		var pl jwt.Payload
+		_, _ = jwt.Verify(token, alg, &pl)
+		_, _ = jwt.ParseUnverified(token, &pl)
+		if rt, err := jwt.Decode(token); err == nil {
+			_ = rt.SigningInput()
+		}
+	})
+}
@@ -5,6 +5,9 @@ import (
 	"sync"
 )
 
+// hashPool reuses hashes across signatures in order to cut allocations.
+// A hash is taken out of the pool for the whole computation and reset before being put back,
+// so it's never shared between goroutines while in use.
 type hashPool struct {
 	*sync.Pool
 }
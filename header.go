@@ -1,11 +1,57 @@
 package jwt
 
+import "encoding/json"
+
+// headerParams are the header parameters that are fields of Header. All of them but "b64",
+// which is defined by the RFC 7797, are registered by the RFC 7515 and RFC 7516,
+// so they must not be listed in the "crit" header parameter.
+var headerParams = map[string]struct{}{
+	"alg": {}, "jku": {}, "jwk": {}, "kid": {}, "x5u": {}, "x5c": {},
+	"x5t": {}, "x5t#S256": {}, "typ": {}, "cty": {}, "crit": {}, "b64": {}, "enc": {}, "zip": {},
+}
+
 // Header is a JOSE header narrowed down to the JWT specification from RFC 7519.
 //
 // Parameters are ordered according to the RFC 7515.
+//
+// Parameters that are not fields of Header are kept in Extra,
+// which is marshaled along with the other parameters when signing and filled when verifying.
+// Like Payload, parameters are marshaled in a fixed order, with those in Extra sorted by name.
 type Header struct {
+	// Algorithm is the "alg" parameter. In a Header returned by a successful Verify,
+	// it matches the name of the algorithm that verified the signature.
 	Algorithm   string `json:"alg,omitempty"`
 	ContentType string `json:"cty,omitempty"`
 	KeyID       string `json:"kid,omitempty"`
 	Type        string `json:"typ,omitempty"`
+	JWKSetURL   string `json:"jku,omitempty"`
+	JWK         *JWK   `json:"jwk,omitempty"`
+	X509URL     string `json:"x5u,omitempty"`
+	// X509CertChain holds standard Base64 encoded DER certificates, as per the RFC 7515.
+	X509CertChain []string `json:"x5c,omitempty"`
+	// X509Thumbprint and X509ThumbprintS256 are Base64URL encoded certificate digests.
+	X509Thumbprint     string `json:"x5t,omitempty"`
+	X509ThumbprintS256 string `json:"x5t#S256,omitempty"`
+	// Critical lists extension parameters that recipients must understand, as per the RFC 7515.
+	// Verify rejects tokens listing parameters not registered with the CriticalParams option.
+	Critical []string `json:"crit,omitempty"`
+	// Base64 is set to false when the payload is not Base64URL encoded, as per the RFC 7797.
+	// Such tokens can only be created and verified with SignDetached and VerifyDetached.
+	Base64 *bool `json:"b64,omitempty"`
+	// Encryption is the content encryption algorithm of a JWE, as per the RFC 7516.
+	Encryption string `json:"enc,omitempty"`
+	// Compression is the compression algorithm applied to a JWE's payload before encryption.
+	// Only "DEF" is supported, and only by EncryptClaims and DecryptClaims.
+	Compression string `json:"zip,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// marshalHeader marshals hd along with its extra parameters.
+func marshalHeader(hd Header) ([]byte, error) {
+	hb, err := JSONMarshal(hd)
+	if err != nil {
+		return nil, err
+	}
+	return appendMembers(hb, hd.Extra, headerParams)
 }
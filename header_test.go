@@ -0,0 +1,51 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHeaderParams(t *testing.T) {
+	want := jwt.Header{
+		Algorithm: "HS256",
+		Type:      "JWT",
+		JWKSetURL: "https://example.com/jwks.json",
+		JWK: &jwt.JWK{
+			KeyType: "EC",
+			Curve:   "P-256",
+			X:       encodeJWKInt(es256PublicKey1.X),
+			Y:       encodeJWKInt(es256PublicKey1.Y),
+		},
+		X509URL:            "https://example.com/cert.pem",
+		X509CertChain:      []string{"MIIB", "MIIC"},
+		X509Thumbprint:     "dGh1bWJwcmludA",
+		X509ThumbprintS256: "dGh1bWJwcmludFMyNTY",
+		Extra: map[string]json.RawMessage{
+			"foo": json.RawMessage(`"bar"`),
+			"num": json.RawMessage(`1`),
+		},
+	}
+	setHeader := func(hd *jwt.Header) {
+		alg, typ := hd.Algorithm, hd.Type
+		*hd = want
+		hd.Algorithm, hd.Type = alg, typ
+		hd.Extra["alg"] = json.RawMessage(`"none"`) // registered parameters are skipped
+	}
+	token, err := jwt.Sign(tp, jwt.NewHS256(hmacKey1), setHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(want.Extra, "alg")
+
+	var pl testPayload
+	hd, err := jwt.Verify(token, jwt.NewHS256(hmacKey1), &pl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hd; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,132 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"sync"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+var _ Algorithm = new(HMACKeyFunc)
+
+// HMACKeyFunc is an algorithm that uses HMAC to sign SHA hashes with keys returned by a function,
+// which is called for every signature, so keys can be rotated without creating a new algorithm.
+// For example, keys derived with HKDF from a master secret and the current time window
+// can be rotated without distributing new secrets.
+type HMACKeyFunc struct {
+	name string
+	keys func() [][]byte
+	sha  crypto.Hash
+
+	mu   sync.Mutex
+	algs []*HMACSHA
+}
+
+func newHMACKeyFunc(name string, keys func() [][]byte, sha crypto.Hash) *HMACKeyFunc {
+	if keys == nil {
+		panic(ErrHMACMissingKey)
+	}
+	return &HMACKeyFunc{name: name, keys: keys, sha: sha}
+}
+
+// NewHS256KeyFunc creates a new algorithm using HMAC and SHA-256 with the keys returned by keys,
+// usually the ones for the current time window followed by the previous ones.
+func NewHS256KeyFunc(keys func() [][]byte) *HMACKeyFunc {
+	return newHMACKeyFunc("HS256", keys, crypto.SHA256)
+}
+
+// NewHS384KeyFunc creates a new algorithm using HMAC and SHA-384 with the keys returned by keys.
+func NewHS384KeyFunc(keys func() [][]byte) *HMACKeyFunc {
+	return newHMACKeyFunc("HS384", keys, crypto.SHA384)
+}
+
+// NewHS512KeyFunc creates a new algorithm using HMAC and SHA-512 with the keys returned by keys.
+func NewHS512KeyFunc(keys func() [][]byte) *HMACKeyFunc {
+	return newHMACKeyFunc("HS512", keys, crypto.SHA512)
+}
+
+// Name returns the algorithm's name.
+func (hk *HMACKeyFunc) Name() string {
+	return hk.name
+}
+
+// Sign signs headerPayload using the first key returned by the key function.
+func (hk *HMACKeyFunc) Sign(headerPayload []byte) ([]byte, error) {
+	algs, err := hk.algorithms()
+	if err != nil {
+		return nil, err
+	}
+	return algs[0].Sign(headerPayload)
+}
+
+// Size returns the signature's byte size.
+func (hk *HMACKeyFunc) Size() int {
+	return hk.sha.Size()
+}
+
+// Verify verifies a signature based on headerPayload using every key returned by the key function.
+// Like KeySet, all keys are always tried and signatures are compared in constant time,
+// so the time spent doesn't depend on which key matches. Empty keys are rejected
+// with ErrHMACMissingKey before any signature is computed.
+func (hk *HMACKeyFunc) Verify(headerPayload, sig []byte) (err error) {
+	if sig, err = internal.DecodeToBytes(sig); err != nil {
+		return err
+	}
+	algs, err := hk.algorithms()
+	if err != nil {
+		return err
+	}
+	match := false
+	for _, alg := range algs {
+		sig2, err := alg.Sign(headerPayload)
+		if err != nil {
+			return err
+		}
+		if hmac.Equal(sig, sig2) {
+			match = true
+		}
+	}
+	if !match {
+		return ErrHMACVerification
+	}
+	return nil
+}
+
+// algorithms returns an HMAC-SHA algorithm for each key returned by the key function.
+// They are reused while the keys don't change, so their hashes are pooled like the ones of HMACSHA.
+func (hk *HMACKeyFunc) algorithms() ([]*HMACSHA, error) {
+	keys := hk.keys()
+	if len(keys) == 0 {
+		return nil, ErrHMACMissingKey
+	}
+	for _, key := range keys {
+		if len(key) == 0 {
+			return nil, ErrHMACMissingKey
+		}
+	}
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+	if !hk.same(keys) {
+		algs := make([]*HMACSHA, len(keys))
+		for i, key := range keys {
+			// Keys are copied, since the key function may reuse their memory.
+			algs[i] = newHMACSHA(hk.name, append([]byte(nil), key...), hk.sha)
+		}
+		hk.algs = algs
+	}
+	return hk.algs, nil
+}
+
+// same reports whether keys are the ones of the cached algorithms. It must be called with mu held.
+func (hk *HMACKeyFunc) same(keys [][]byte) bool {
+	if len(keys) != len(hk.algs) {
+		return false
+	}
+	for i, key := range keys {
+		if !hmac.Equal(key, hk.algs[i].key) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package jwt_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"strconv"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHMACKeyFunc(t *testing.T) {
+	// deriveKey simulates deriving a key for a time window from a master secret.
+	deriveKey := func(window int) []byte {
+		hh := hmac.New(sha256.New, []byte("master secret"))
+		hh.Write([]byte(strconv.Itoa(window)))
+		return hh.Sum(nil)
+	}
+	window := 1
+	keys := func() [][]byte { return [][]byte{deriveKey(window), deriveKey(window - 1)} }
+	alg := jwt.NewHS256KeyFunc(keys)
+
+	token, err := jwt.Sign(tp, alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tokens are interoperable with HMACSHA.
+	want, err := jwt.Sign(tp, jwt.NewHS256(deriveKey(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(token) != string(want) {
+		t.Errorf("jwt.Sign mismatch (-want +got):\n%s", cmp.Diff(string(want), string(token)))
+	}
+
+	testCases := []struct {
+		window int
+		err    error
+	}{
+		{1, nil},
+		{2, nil},
+		{3, jwt.ErrHMACVerification},
+	}
+	for _, tc := range testCases {
+		t.Run(strconv.Itoa(tc.window), func(t *testing.T) {
+			window = tc.window
+			_, err := jwt.Verify(token, alg, &jwt.Payload{})
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+
+	t.Run("no keys", func(t *testing.T) {
+		alg := jwt.NewHS512KeyFunc(func() [][]byte { return nil })
+		if _, err := jwt.Sign(tp, alg); err != jwt.ErrHMACMissingKey {
+			t.Errorf("jwt.Sign err mismatch (-want +got):\n%s", cmp.Diff(jwt.ErrHMACMissingKey, err))
+		}
+		_, err := jwt.Verify(token, jwt.NewHS256KeyFunc(func() [][]byte { return [][]byte{nil} }), &jwt.Payload{})
+		if want, got := jwt.ErrHMACMissingKey, err; got != want {
+			t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+	t.Run("empty key", func(t *testing.T) {
+		alg := jwt.NewHS256KeyFunc(func() [][]byte { return [][]byte{deriveKey(1), {}} })
+		_, err := jwt.Verify(token, alg, &jwt.Payload{})
+		if want, got := jwt.ErrHMACMissingKey, err; got != want {
+			t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+	t.Run("reused key memory", func(t *testing.T) {
+		key := deriveKey(1)
+		alg := jwt.NewHS256KeyFunc(func() [][]byte { return [][]byte{key} })
+		if _, err := jwt.Verify(token, alg, &jwt.Payload{}); err != nil {
+			t.Fatal(err)
+		}
+		copy(key, deriveKey(3))
+		_, err := jwt.Verify(token, alg, &jwt.Payload{})
+		if want, got := jwt.ErrHMACVerification, err; !internal.ErrorIs(got, want) {
+			t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+}
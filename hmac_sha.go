@@ -12,7 +12,9 @@ var (
 	// ErrHMACMissingKey is the error for trying to sign or verify a JWT with an empty key.
 	ErrHMACMissingKey = internal.NewError("jwt: HMAC key is empty")
 	// ErrHMACVerification is the error for an invalid signature.
-	ErrHMACVerification = internal.NewError("jwt: HMAC verification failed")
+	ErrHMACVerification = internal.WrapError("jwt: HMAC verification failed", ErrSignatureMismatch)
+	// ErrHMACKeyTooShort is the error for a key shorter than the hash output, as per the RFC 7518.
+	ErrHMACKeyTooShort = internal.NewError("jwt: HMAC key is too short")
 
 	_ Algorithm = new(HMACSHA)
 )
@@ -40,16 +42,19 @@ func newHMACSHA(name string, key []byte, sha crypto.Hash) *HMACSHA {
 }
 
 // NewHS256 creates a new algorithm using HMAC and SHA-256.
+// The key should be at least 32 bytes long, which can be checked with ValidateKey.
 func NewHS256(key []byte) *HMACSHA {
 	return newHMACSHA("HS256", key, crypto.SHA256)
 }
 
 // NewHS384 creates a new algorithm using HMAC and SHA-384.
+// The key should be at least 48 bytes long, which can be checked with ValidateKey.
 func NewHS384(key []byte) *HMACSHA {
 	return newHMACSHA("HS384", key, crypto.SHA384)
 }
 
 // NewHS512 creates a new algorithm using HMAC and SHA-512.
+// The key should be at least 64 bytes long, which can be checked with ValidateKey.
 func NewHS512(key []byte) *HMACSHA {
 	return newHMACSHA("HS512", key, crypto.SHA512)
 }
@@ -59,6 +64,16 @@ func (hs *HMACSHA) Name() string {
 	return hs.name
 }
 
+// ValidateKey checks whether the key is at least as long as the hash output, as required by the RFC 7518.
+// Shorter keys are accepted by the constructors for backward compatibility, but are easier to brute-force,
+// so calling ValidateKey when loading keys, or in tests, is recommended.
+func (hs *HMACSHA) ValidateKey() error {
+	if len(hs.key) < hs.size {
+		return internal.Errorf("jwt: %s key has %d bytes, want at least %d: %w", hs.name, len(hs.key), hs.size, ErrHMACKeyTooShort)
+	}
+	return nil
+}
+
 // Sign signs headerPayload using the HMAC-SHA algorithm.
 func (hs *HMACSHA) Sign(headerPayload []byte) ([]byte, error) {
 	if string(hs.key) == "" {
@@ -73,6 +88,7 @@ func (hs *HMACSHA) Size() int {
 }
 
 // Verify verifies a signature based on headerPayload using HMAC-SHA.
+// Signatures are compared in constant time.
 func (hs *HMACSHA) Verify(headerPayload, sig []byte) (err error) {
 	if sig, err = internal.DecodeToBytes(sig); err != nil {
 		return err
@@ -1,9 +1,11 @@
 package jwt_test
 
 import (
+	"encoding/base64"
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/gbrlsnchs/jwt/v3"
@@ -50,7 +52,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrHMACVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewHS384(hmacKey1),
@@ -86,7 +88,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrHMACVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewHS512(hmacKey1),
@@ -122,7 +124,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrHMACVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 	}
 )
@@ -165,6 +167,96 @@ func TestNewHMACSHA(t *testing.T) {
 	}
 }
 
+func TestHMACSHAValidateKey(t *testing.T) {
+	testCases := []struct {
+		builder func([]byte) *jwt.HMACSHA
+		size    int
+		err     error
+	}{
+		{jwt.NewHS256, 6, jwt.ErrHMACKeyTooShort},
+		{jwt.NewHS256, 31, jwt.ErrHMACKeyTooShort},
+		{jwt.NewHS256, 32, nil},
+		{jwt.NewHS384, 32, jwt.ErrHMACKeyTooShort},
+		{jwt.NewHS384, 48, nil},
+		{jwt.NewHS512, 63, jwt.ErrHMACKeyTooShort},
+		{jwt.NewHS512, 64, nil},
+		{jwt.NewHS512, 128, nil},
+	}
+	for _, tc := range testCases {
+		funcName := funcName(tc.builder)
+		t.Run(funcName, func(t *testing.T) {
+			err := tc.builder(make([]byte, tc.size)).ValidateKey()
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.HMACSHA.ValidateKey err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestHMACSHAVerifyTampered(t *testing.T) {
+	hs256 := jwt.NewHS256(hmacKey1)
+	token, err := jwt.Sign(jwt.Payload{}, hs256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := strings.LastIndexByte(string(token), '.')
+	sig, err := base64.RawURLEncoding.DecodeString(string(token[i+1:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastByte := append([]byte(nil), sig...)
+	lastByte[len(lastByte)-1] ^= 1
+	testCases := []struct {
+		name string
+		sig  []byte
+		err  error
+	}{
+		{"valid", sig, nil},
+		{"last byte", lastByte, jwt.ErrHMACVerification},
+		{"truncated", sig[:len(sig)-1], jwt.ErrHMACVerification},
+		{"empty", nil, jwt.ErrHMACVerification},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sig64 := []byte(base64.RawURLEncoding.EncodeToString(tc.sig))
+			if want, got := tc.err, hs256.Verify(token[:i], sig64); !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.HMACSHA.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestHMACSHAConcurrency(t *testing.T) {
+	hs256 := jwt.NewHS256(hmacKey1)
+	want, err := hs256.Sign([]byte("header.payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				got, err := hs256.Sign([]byte("header.payload"))
+				if err == nil && string(got) != string(want) {
+					err = jwt.ErrHMACVerification
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
 func funcName(fn interface{}) string {
 	return strings.Split(
 		runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name(),
@@ -15,11 +15,34 @@ func Decode(enc []byte, v interface{}) error {
 }
 
 // DecodeToBytes decodes a Base64 string using the proper encoding for JWTs.
+// Padded input, although not allowed by the RFC 7515, is tolerated for interoperability.
+// Characters outside the Base64URL alphabet are rejected, including line breaks,
+// which encoding/base64 otherwise ignores.
 func DecodeToBytes(enc []byte) ([]byte, error) {
+	if i := invalidIndex(enc); i >= 0 {
+		return nil, base64.CorruptInputError(i)
+	}
 	encoding := base64.RawURLEncoding
+	if len(enc) > 0 && enc[len(enc)-1] == '=' {
+		encoding = base64.URLEncoding
+	}
 	dec := make([]byte, encoding.DecodedLen(len(enc)))
-	if _, err := encoding.Decode(dec, enc); err != nil {
+	n, err := encoding.Decode(dec, enc)
+	if err != nil {
 		return nil, err
 	}
-	return dec, nil
+	return dec[:n], nil
+}
+
+// invalidIndex returns the index of the first character of enc outside the Base64URL alphabet,
+// or -1 if there's none. Trailing padding is left for the decoder to check.
+func invalidIndex(enc []byte) int {
+	for i, c := range enc {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '=':
+		default:
+			return i
+		}
+	}
+	return -1
 }
@@ -26,8 +26,9 @@ func TestDecode(t *testing.T) {
 	}{
 		{rawURLEnc, "{}", "", false},
 		{rawURLEnc, `{"x":"test"}`, "test", false},
-		{stdEnc, "{}", "", true},
+		{stdEnc, "{}", "", false},               // padding is tolerated
 		{stdEnc, `{"x":"test"}`, "test", false}, // the output is the same as with RawURLEncoding
+		{stdEnc, `{"x":"~~~"}`, "", true},       // the standard alphabet is not
 		{nil, "{}", "", true},
 		{nil, `{"x":"test"}`, "", true},
 	}
@@ -51,3 +52,25 @@ func TestDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeToBytesAlphabet(t *testing.T) {
+	testCases := []struct {
+		enc    string
+		errors bool
+	}{
+		{"-_-_", false},
+		{"AA==", false},
+		{"+/+/", true},
+		{"AA\nAA", true},
+		{"AA\r\n", true},
+		{"AA AA", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.enc, func(t *testing.T) {
+			_, err := internal.DecodeToBytes([]byte(tc.enc))
+			if want, got := tc.errors, internal.ErrorAs(err, new(base64.CorruptInputError)); got != want {
+				t.Fatalf("want %t, got %t: %v", want, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package internal
+
+type wrapError struct {
+	msg string
+	err error
+}
+
+// WrapError creates an error with text as its message that wraps err,
+// so a sentinel error can be a more specific kind of another one.
+func WrapError(text string, err error) error { return &wrapError{text, err} }
+
+func (e *wrapError) Error() string { return e.msg }
+
+func (e *wrapError) Unwrap() error { return e.err }
@@ -2,13 +2,76 @@ package jwt
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
 )
 
 // ErrNotJSONObject is the error for when a JWT payload is not a JSON object.
 var ErrNotJSONObject = errors.New("jwt: payload is not a valid JSON object")
 
+// ErrDuplicateKey is the error for when a JSON object has duplicate keys and DisallowDuplicateKeys is set.
+// It wraps ErrMalformed.
+var ErrDuplicateKey = internal.WrapError("jwt: duplicate JSON key", ErrMalformed)
+
+var (
+	// JSONMarshal is the function used for marshaling headers, payloads and private claims,
+	// for example, by Payload.Set. It can be replaced by a faster implementation compatible
+	// with encoding/json. It must not be changed while signing or verifying.
+	// JWKs, JSON Schemas and the Audience and Time types are always marshaled with encoding/json.
+	JSONMarshal = json.Marshal
+	// JSONUnmarshal is the function used for unmarshaling headers, payloads and private claims,
+	// for example, by Payload.Get and validators.
+	// Like JSONMarshal, it can be replaced but must not be changed while verifying.
+	JSONUnmarshal = json.Unmarshal
+)
+
 func isJSONObject(payload []byte) bool {
 	payload = bytes.TrimSpace(payload)
-	return payload[0] == '{' && payload[len(payload)-1] == '}'
+	return len(payload) > 1 && payload[0] == '{' && payload[len(payload)-1] == '}'
+}
+
+// checkDuplicateKeys walks a JSON value and returns ErrDuplicateKey if any object in it,
+// including nested ones, has duplicate keys.
+func checkDuplicateKeys(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return walkJSON(dec)
+}
+
+func walkJSON(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return malformed(err)
+	}
+	switch tok {
+	case json.Delim('{'):
+		keys := make(map[string]struct{})
+		for dec.More() {
+			if tok, err = dec.Token(); err != nil {
+				return malformed(err)
+			}
+			key := tok.(string)
+			if _, ok := keys[key]; ok {
+				return internal.Errorf("jwt: %q: %w", key, ErrDuplicateKey)
+			}
+			keys[key] = struct{}{}
+			if err = walkJSON(dec); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for dec.More() {
+			if err = walkJSON(dec); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+	if _, err = dec.Token(); err != nil { // closing delimiter
+		return malformed(err)
+	}
+	return nil
 }
@@ -0,0 +1,80 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSONFuncs(t *testing.T) {
+	marshal, unmarshal := jwt.JSONMarshal, jwt.JSONUnmarshal
+	defer func() {
+		jwt.JSONMarshal, jwt.JSONUnmarshal = marshal, unmarshal
+	}()
+
+	var marshals, unmarshals int
+	jwt.JSONMarshal = func(v interface{}) ([]byte, error) {
+		marshals++
+		return json.Marshal(v)
+	}
+	jwt.JSONUnmarshal = func(data []byte, v interface{}) error {
+		unmarshals++
+		return json.Unmarshal(data, v)
+	}
+
+	hs256 := jwt.NewHS256(hmacKey1)
+	token, err := jwt.Sign(tp, hs256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pl testPayload
+	if _, err = jwt.Verify(token, hs256, &pl); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := tp, pl; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := 2, marshals; got != want { // header and payload
+		t.Errorf("jwt.JSONMarshal calls mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := 3, unmarshals; got != want { // header, its extra parameters and payload
+		t.Errorf("jwt.JSONUnmarshal calls mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestJSONFuncsPrivateClaims(t *testing.T) {
+	marshal, unmarshal := jwt.JSONMarshal, jwt.JSONUnmarshal
+	defer func() {
+		jwt.JSONMarshal, jwt.JSONUnmarshal = marshal, unmarshal
+	}()
+
+	var marshals, unmarshals int
+	jwt.JSONMarshal = func(v interface{}) ([]byte, error) {
+		marshals++
+		return json.Marshal(v)
+	}
+	jwt.JSONUnmarshal = func(data []byte, v interface{}) error {
+		unmarshals++
+		return json.Unmarshal(data, v)
+	}
+
+	var pl jwt.Payload
+	if err := pl.Set("foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	var foo string
+	if err := pl.Get("foo", &foo); err != nil {
+		t.Fatal(err)
+	}
+	if err := jwt.ClaimValidator("foo", "bar")(&pl); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 2, marshals; got != want { // Set and ClaimValidator
+		t.Errorf("jwt.JSONMarshal calls mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := 3, unmarshals; got != want { // Get and ClaimValidator
+		t.Errorf("jwt.JSONUnmarshal calls mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,241 @@
+package jwt
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+const (
+	jweAlgorithm  = "RSA-OAEP"
+	jweEncryption = "A256GCM"
+
+	cekSize = 32 // AES-256
+
+	deflate = "DEF"
+
+	// DefaultMaxDecompressedSize is the maximum byte size of a compressed JWE's payload after being
+	// decompressed by DecryptClaims, which protects against decompression bombs,
+	// unless overridden by the MaxDecompressedSize option.
+	DefaultMaxDecompressedSize int64 = 1 << 20
+)
+
+var (
+	// ErrJWEUnsupported is the error for when a JWE's "alg" or "enc" header parameters are not supported.
+	ErrJWEUnsupported = internal.NewError("jwt: JWE algorithm is not supported")
+	// ErrJWEDecryption is the error for when a JWE can't be decrypted.
+	ErrJWEDecryption = internal.NewError("jwt: JWE decryption failed")
+	// ErrJWETooLarge is the error for when a JWE's payload exceeds the maximum size after decompression.
+	ErrJWETooLarge = internal.NewError("jwt: JWE payload is too large")
+)
+
+// EncryptClaims encrypts a payload for the owner of pub, as a JWE in compact serialization.
+// The content encryption key is wrapped with RSA-OAEP and the payload is encrypted with AES-256-GCM,
+// so the "alg" and "enc" header parameters are set to "RSA-OAEP" and "A256GCM", respectively.
+//
+// Like Sign, it marshals payload along with its private claims and runs opts against the header.
+// If the Deflate option is passed, the payload is compressed before encryption.
+func EncryptClaims(payload interface{}, pub *rsa.PublicKey, opts ...SignOption) ([]byte, error) {
+	if pub == nil {
+		return nil, ErrRSANilPubKey
+	}
+	var hd Header
+	for _, opt := range opts {
+		opt(&hd)
+	}
+	if hd.Compression != "" && hd.Compression != deflate {
+		return nil, internal.Errorf("jwt: %q compression: %w", hd.Compression, ErrJWEUnsupported)
+	}
+	hd.Algorithm = jweAlgorithm
+	hd.Encryption = jweEncryption
+	if hd.Type == "" {
+		hd.Type = "JWT"
+	}
+	hb, err := marshalHeader(hd)
+	if err != nil {
+		return nil, err
+	}
+	pb, err := marshalPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	if hd.Compression == deflate {
+		if pb, err = compress(pb); err != nil {
+			return nil, err
+		}
+	}
+
+	cek := make([]byte, cekSize)
+	if _, err = io.ReadFull(rand.Reader, cek); err != nil {
+		return nil, err
+	}
+	ek, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pub, cek, nil)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	enc := base64.RawURLEncoding
+	protected := make([]byte, enc.EncodedLen(len(hb)))
+	enc.Encode(protected, hb)
+	sealed := gcm.Seal(nil, iv, pb, protected) // the protected header is the additional authenticated data
+	ct, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	token := bytes.NewBuffer(make([]byte, 0, len(protected)+4+
+		enc.EncodedLen(len(ek))+enc.EncodedLen(len(iv))+enc.EncodedLen(len(ct))+enc.EncodedLen(len(tag))))
+	token.Write(protected)
+	for _, part := range [][]byte{ek, iv, ct, tag} {
+		token.WriteByte('.')
+		encoded := make([]byte, enc.EncodedLen(len(part)))
+		enc.Encode(encoded, part)
+		token.Write(encoded)
+	}
+	return token.Bytes(), nil
+}
+
+// DecryptClaims decrypts a JWE created by EncryptClaims using priv and unmarshals its payload.
+// Before decoding, opts is iterated and each option in it is run, so validators can be set
+// with ValidatePayload and extension parameters can be registered with CriticalParams.
+//
+// It returns the JWE's decoded Header, even if decryption fails, as long as it could be decoded.
+// Any decryption failure, including a wrong key or a tampered token, results in ErrJWEDecryption.
+func DecryptClaims(token []byte, priv *rsa.PrivateKey, payload interface{}, opts ...VerifyOption) (Header, error) {
+	rt := RawToken{
+		maxTokenSize:        DefaultMaxTokenSize,
+		maxHeaderSize:       DefaultMaxHeaderSize,
+		maxDecompressedSize: DefaultMaxDecompressedSize,
+	}
+	if priv == nil {
+		return rt.hd, ErrRSANilPrivKey
+	}
+	for _, opt := range opts {
+		if err := opt(&rt); err != nil {
+			return rt.hd, err
+		}
+	}
+	if err := checkSize(token, rt.maxTokenSize, rt.maxHeaderSize); err != nil {
+		return rt.hd, err
+	}
+	parts := bytes.Split(token, []byte{'.'})
+	if len(parts) != 5 {
+		return rt.hd, ErrMalformed
+	}
+	hb, err := internal.DecodeToBytes(parts[0])
+	if err != nil {
+		return rt.hd, malformed(err)
+	}
+	if err = rt.unmarshalHeader(hb); err != nil {
+		return rt.hd, err
+	}
+	if rt.hd.Algorithm != jweAlgorithm || rt.hd.Encryption != jweEncryption {
+		return rt.hd, internal.Errorf("jwt: %q and %q: %w", rt.hd.Algorithm, rt.hd.Encryption, ErrJWEUnsupported)
+	}
+	if rt.hd.Compression != "" && rt.hd.Compression != deflate {
+		return rt.hd, internal.Errorf("jwt: %q compression: %w", rt.hd.Compression, ErrJWEUnsupported)
+	}
+	if err = rt.checkHeader(); err != nil {
+		return rt.hd, err
+	}
+	if err = rt.validateCritical(); err != nil {
+		return rt.hd, err
+	}
+
+	decoded := make([][]byte, 4)
+	for i, part := range parts[1:] {
+		if decoded[i], err = internal.DecodeToBytes(part); err != nil {
+			return rt.hd, malformed(err)
+		}
+	}
+	ek, iv, ct, tag := decoded[0], decoded[1], decoded[2], decoded[3]
+	cek, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, priv, ek, nil)
+	if err != nil || len(cek) != cekSize {
+		return rt.hd, ErrJWEDecryption
+	}
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return rt.hd, err
+	}
+	if len(iv) != gcm.NonceSize() || len(tag) != gcm.Overhead() {
+		return rt.hd, ErrJWEDecryption
+	}
+	pb, err := gcm.Open(nil, iv, append(ct, tag...), parts[0])
+	if err != nil {
+		return rt.hd, ErrJWEDecryption
+	}
+	if rt.hd.Compression == deflate {
+		if pb, err = decompress(pb, rt.maxDecompressedSize); err != nil {
+			return rt.hd, err
+		}
+	}
+	return rt.hd, rt.unmarshal(pb, payload)
+}
+
+// Deflate is an option for compressing a JWE's payload with DEFLATE before encryption.
+// It sets the "zip" header parameter to "DEF" and is only supported by EncryptClaims.
+func Deflate() SignOption {
+	return func(hd *Header) {
+		hd.Compression = deflate
+	}
+}
+
+// MaxDecompressedSize is an option for DecryptClaims to set the maximum byte size of a compressed
+// JWE's payload after being decompressed, overriding DefaultMaxDecompressedSize.
+// It only applies to DecryptClaims, and Verify and the other verifying functions ignore it,
+// since JWSs aren't compressed.
+func MaxDecompressedSize(n int64) VerifyOption {
+	return func(rt *RawToken) error {
+		rt.maxDecompressedSize = n
+		return nil
+	}
+}
+
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompress(data []byte, max int64) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, malformed(err)
+	}
+	if int64(len(data)) > max {
+		return nil, internal.Errorf("jwt: more than %d bytes: %w", max, ErrJWETooLarge)
+	}
+	return data, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,182 @@
+package jwt_test
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"strings"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJWE(t *testing.T) {
+	token, err := jwt.EncryptClaims(tp, rsaPublicKey1, jwt.KeyID("enc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 4, bytes.Count(token, []byte{'.'}); got != want {
+		t.Fatalf("jwt.EncryptClaims separator count mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	parts := bytes.Split(token, []byte{'.'})
+	tampered := append([]byte(nil), token...)
+	ct := len(parts[0]) + len(parts[1]) + len(parts[2]) + 3 // first byte of the ciphertext
+	if tampered[ct] = 'A'; token[ct] == 'A' {
+		tampered[ct] = 'B'
+	}
+	garbled := func(i int) []byte {
+		garbled := append([][]byte(nil), parts...)
+		garbled[i] = []byte("!!!")
+		return bytes.Join(garbled, []byte{'.'})
+	}
+	signed, err := jwt.Sign(tp, jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHeader := jwt.Header{Algorithm: "RSA-OAEP", Encryption: "A256GCM", KeyID: "enc", Type: "JWT"}
+
+	testCases := []struct {
+		name        string
+		token       []byte
+		priv        *rsa.PrivateKey
+		opts        []jwt.VerifyOption
+		vds         []jwt.Validator
+		wantHeader  jwt.Header
+		wantPayload testPayload
+		err         error
+	}{
+		{
+			name:        "valid",
+			token:       token,
+			priv:        rsaPrivateKey1,
+			wantHeader:  wantHeader,
+			wantPayload: tp,
+		},
+		{
+			name:       "wrong key",
+			token:      token,
+			priv:       rsaPrivateKey2,
+			wantHeader: wantHeader,
+			err:        jwt.ErrJWEDecryption,
+		},
+		{
+			name:       "tampered",
+			token:      tampered,
+			priv:       rsaPrivateKey1,
+			wantHeader: wantHeader,
+			err:        jwt.ErrJWEDecryption,
+		},
+		{
+			name:        "validators",
+			token:       token,
+			priv:        rsaPrivateKey1,
+			vds:         []jwt.Validator{jwt.IssuerValidator(tp.Issuer), jwt.SubjectValidator(tp.Subject)},
+			wantHeader:  wantHeader,
+			wantPayload: tp,
+		},
+		{
+			name:        "invalid",
+			token:       token,
+			priv:        rsaPrivateKey1,
+			vds:         []jwt.Validator{jwt.SubjectValidator("other")},
+			wantHeader:  wantHeader,
+			wantPayload: tp,
+			err:         jwt.ErrSubValidation,
+		},
+		{
+			name:  "JWS",
+			token: signed,
+			priv:  rsaPrivateKey1,
+			err:   jwt.ErrMalformed,
+		},
+		{
+			name:  "too large",
+			token: append(bytes.Repeat([]byte{'A'}, jwt.DefaultMaxHeaderSize), token...),
+			priv:  rsaPrivateKey1,
+			err:   jwt.ErrTokenTooLarge,
+		},
+		{
+			name:  "header not Base64URL",
+			token: garbled(0),
+			priv:  rsaPrivateKey1,
+			err:   jwt.ErrMalformed,
+		},
+		{
+			name:       "ciphertext not Base64URL",
+			token:      garbled(3),
+			priv:       rsaPrivateKey1,
+			wantHeader: wantHeader,
+			err:        jwt.ErrMalformed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pl testPayload
+			opts := tc.opts
+			if tc.vds != nil {
+				opts = append(opts, jwt.ValidatePayload(&pl.Payload, tc.vds...))
+			}
+			hd, err := jwt.DecryptClaims(tc.token, tc.priv, &pl, opts...)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.DecryptClaims err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantHeader, hd; !cmp.Equal(got, want) {
+				t.Errorf("jwt.DecryptClaims header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantPayload, pl; !cmp.Equal(got, want) {
+				t.Errorf("jwt.DecryptClaims payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestJWEDeflate(t *testing.T) {
+	pl := testPayload{String: strings.Repeat("compressible ", 1000)}
+	token, err := jwt.EncryptClaims(pl, rsaPublicKey1, jwt.Deflate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max := len(pl.String) / 2; len(token) > max {
+		t.Errorf("jwt.EncryptClaims token is %d bytes long, want at most %d", len(token), max)
+	}
+	var got testPayload
+	hd, err := jwt.DecryptClaims(token, rsaPrivateKey1, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "DEF", hd.Compression; got != want {
+		t.Errorf("jwt.DecryptClaims header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want := pl; !cmp.Equal(got, want) {
+		t.Errorf("jwt.DecryptClaims payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	t.Run("too large", func(t *testing.T) {
+		_, err := jwt.DecryptClaims(token, rsaPrivateKey1, &got, jwt.MaxDecompressedSize(int64(len(pl.String))))
+		if want, got := jwt.ErrJWETooLarge, err; !internal.ErrorIs(got, want) {
+			t.Errorf("jwt.DecryptClaims err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+	t.Run("raised limit", func(t *testing.T) {
+		large := testPayload{String: strings.Repeat("a", int(jwt.DefaultMaxDecompressedSize))}
+		token, err := jwt.EncryptClaims(large, rsaPublicKey1, jwt.Deflate())
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = jwt.DecryptClaims(token, rsaPrivateKey1, &got)
+		if want, got := jwt.ErrJWETooLarge, err; !internal.ErrorIs(got, want) {
+			t.Errorf("jwt.DecryptClaims err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+		if _, err = jwt.DecryptClaims(token, rsaPrivateKey1, &got, jwt.MaxDecompressedSize(2*jwt.DefaultMaxDecompressedSize)); err != nil {
+			t.Errorf("jwt.DecryptClaims with a raised limit: %v", err)
+		}
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		zip := func(hd *jwt.Header) { hd.Compression = "GZIP" }
+		_, err := jwt.EncryptClaims(pl, rsaPublicKey1, zip)
+		if want, got := jwt.ErrJWEUnsupported, err; !internal.ErrorIs(got, want) {
+			t.Errorf("jwt.EncryptClaims err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+}
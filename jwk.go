@@ -0,0 +1,249 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+var (
+	// ErrJWKInvalid is the error for when a JWK's parameters are missing or invalid.
+	ErrJWKInvalid = internal.NewError("jwt: JWK is invalid")
+	// ErrJWKUnsupported is the error for when a JWK's key type, curve or algorithm is not supported.
+	ErrJWKUnsupported = internal.NewError("jwt: JWK is not supported")
+)
+
+// JWK is a JSON Web Key according to the RFC 7517,
+// narrowed down to public RSA and elliptic curve keys.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+
+	// RSA parameters.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Elliptic curve parameters.
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// JWKSet is a JWK Set according to the RFC 7517.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ParseJWK parses a JSON encoded JWK and creates an algorithm for verifying signatures with it,
+// as returned by the JWK's NewAlgorithm method with an empty algorithm name.
+// JWKs for RSA keys must thus have the "alg" parameter set, and opts are passed to NewAlgorithm.
+func ParseJWK(data []byte, opts ...func(*RSASHA)) (Algorithm, error) {
+	var jwk JWK
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, internal.Errorf("jwt: %v: %w", err, ErrJWKInvalid)
+	}
+	return jwk.NewAlgorithm("", opts...)
+}
+
+// ParseJWKSet parses a JSON encoded JWK Set and creates algorithms for verifying signatures
+// with all of its keys, mapped by their "kid" parameter, which must be unique.
+// Like ParseJWK, RSA keys must have the "alg" parameter set, and opts are passed to NewAlgorithm.
+// Keys not meant for signatures, as per the "use" parameter, are skipped.
+func ParseJWKSet(data []byte, opts ...func(*RSASHA)) (map[string]Algorithm, error) {
+	var set JWKSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, internal.Errorf("jwt: %v: %w", err, ErrJWKInvalid)
+	}
+	algs := make(map[string]Algorithm, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if _, ok := algs[jwk.KeyID]; ok {
+			return nil, internal.Errorf("jwt: duplicate %q key ID: %w", jwk.KeyID, ErrJWKInvalid)
+		}
+		alg, err := jwk.NewAlgorithm("", opts...)
+		if err != nil {
+			return nil, err
+		}
+		algs[jwk.KeyID] = alg
+	}
+	return algs, nil
+}
+
+// Thumbprint computes the JWK thumbprint of an *rsa.PublicKey or *ecdsa.PublicKey as per the RFC 7638,
+// that is, the Base64URL encoded SHA-256 hash of its required JWK parameters.
+// It's stable, so it can be used as a key ID by both issuers and verifiers.
+func Thumbprint(key crypto.PublicKey) (string, error) {
+	var members string
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		members = `{"e":"` + encodeJWKInt(big.NewInt(int64(key.E)).Bytes()) +
+			`","kty":"RSA","n":"` + encodeJWKInt(key.N.Bytes()) + `"}`
+	case *ecdsa.PublicKey:
+		crv, ok := curveNames[key.Curve]
+		if !ok {
+			return "", internal.Errorf("jwt: %q curve: %w", key.Curve.Params().Name, ErrJWKUnsupported)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		members = `{"crv":"` + crv + `","kty":"EC","x":"` + encodeJWKInt(padLeft(key.X.Bytes(), size)) +
+			`","y":"` + encodeJWKInt(padLeft(key.Y.Bytes(), size)) + `"}`
+	default:
+		return "", internal.Errorf("jwt: %T key: %w", key, ErrJWKUnsupported)
+	}
+	sum := sha256.Sum256([]byte(members))
+	return encodeJWKInt(sum[:]), nil
+}
+
+// PublicKey decodes the public key represented by the JWK.
+// It returns either an *rsa.PublicKey or an *ecdsa.PublicKey.
+func (jwk *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		return jwk.rsaPublicKey()
+	case "EC":
+		return jwk.ecdsaPublicKey()
+	default:
+		return nil, internal.Errorf("jwt: %q key type: %w", jwk.KeyType, ErrJWKUnsupported)
+	}
+}
+
+// NewAlgorithm creates an algorithm for verifying signatures using the JWK's public key.
+//
+// If alg is empty, the algorithm is the one set in the JWK's "alg" parameter or, for elliptic curve keys,
+// the one implied by the curve. If both alg and the JWK's "alg" parameter are set, they must match.
+// RSA keys shorter than DefaultMinRSAKeySize are rejected with ErrRSAKeyTooSmall, so a compromised
+// or buggy issuer can't publish a forgeable key. For RSA keys, opts are passed to the RSA-SHA constructor,
+// so the minimum can be overridden with RSAMinKeySize.
+func (jwk *JWK) NewAlgorithm(alg string, opts ...func(*RSASHA)) (Algorithm, error) {
+	if alg == "" {
+		alg = jwk.Algorithm
+	}
+	if jwk.Algorithm != "" && jwk.Algorithm != alg {
+		return nil, internal.Errorf("jwt: %q mismatches JWK's %q: %w", alg, jwk.Algorithm, ErrAlgValidation)
+	}
+	pub, err := jwk.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		opts = append([]func(*RSASHA){RSAPublicKey(pub)}, opts...)
+		if err = checkRSAKeySize(pub, minRSAKeySize(opts)); err != nil {
+			return nil, err
+		}
+		switch alg {
+		case "RS256":
+			return NewRS256(opts...), nil
+		case "RS384":
+			return NewRS384(opts...), nil
+		case "RS512":
+			return NewRS512(opts...), nil
+		case "PS256":
+			return NewPS256(opts...), nil
+		case "PS384":
+			return NewPS384(opts...), nil
+		case "PS512":
+			return NewPS512(opts...), nil
+		}
+	case *ecdsa.PublicKey:
+		curveAlg := map[string]string{"P-256": "ES256", "P-384": "ES384", "P-521": "ES512"}[jwk.Curve]
+		if alg == "" {
+			alg = curveAlg
+		}
+		if alg != curveAlg {
+			return nil, internal.Errorf("jwt: %q mismatches %q curve: %w", alg, jwk.Curve, ErrAlgValidation)
+		}
+		opt := ECDSAPublicKey(pub)
+		switch alg {
+		case "ES256":
+			return NewES256(opt), nil
+		case "ES384":
+			return NewES384(opt), nil
+		case "ES512":
+			return NewES512(opt), nil
+		}
+	}
+	return nil, internal.Errorf("jwt: %q algorithm for %q key type: %w", alg, jwk.KeyType, ErrJWKUnsupported)
+}
+
+func (jwk *JWK) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeJWKInt(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeJWKInt(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	if e.BitLen() > 31 || e.Int64() < 2 {
+		return nil, internal.Errorf(`jwt: "e" is out of range: %w`, ErrJWKInvalid)
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (jwk *JWK) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var c elliptic.Curve
+	switch jwk.Curve {
+	case "P-256":
+		c = elliptic.P256()
+	case "P-384":
+		c = elliptic.P384()
+	case "P-521":
+		c = elliptic.P521()
+	default:
+		return nil, internal.Errorf("jwt: %q curve: %w", jwk.Curve, ErrJWKUnsupported)
+	}
+	x, err := decodeJWKInt(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeJWKInt(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+	if !c.IsOnCurve(x, y) {
+		return nil, internal.Errorf("jwt: point is not on %q curve: %w", jwk.Curve, ErrJWKInvalid)
+	}
+	return &ecdsa.PublicKey{Curve: c, X: x, Y: y}, nil
+}
+
+var curveNames = map[elliptic.Curve]string{
+	elliptic.P256(): "P-256",
+	elliptic.P384(): "P-384",
+	elliptic.P521(): "P-521",
+}
+
+func encodeJWKInt(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func padLeft(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
+
+// decodeJWKInt decodes a Base64URL encoded big-endian unsigned integer.
+func decodeJWKInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, internal.Errorf("jwt: missing parameter: %w", ErrJWKInvalid)
+	}
+	b, err := internal.DecodeToBytes([]byte(s))
+	if err != nil {
+		return nil, internal.Errorf("jwt: %v: %w", err, ErrJWKInvalid)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,203 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJWKNewAlgorithm(t *testing.T) {
+	rsaJWK := jwt.JWK{
+		KeyType: "RSA",
+		N:       encodeJWKInt(rsaPublicKey1.N),
+		E:       encodeJWKInt(big.NewInt(int64(rsaPublicKey1.E))),
+	}
+	es256JWK := jwt.JWK{
+		KeyType: "EC",
+		Curve:   "P-256",
+		X:       encodeJWKInt(es256PublicKey1.X),
+		Y:       encodeJWKInt(es256PublicKey1.Y),
+	}
+	es384JWK := jwt.JWK{
+		KeyType: "EC",
+		Curve:   "P-384",
+		X:       encodeJWKInt(es384PublicKey1.X),
+		Y:       encodeJWKInt(es384PublicKey1.Y),
+	}
+	rs384JWK := rsaJWK
+	rs384JWK.Algorithm = "RS384"
+	invalidPoint := es256JWK
+	invalidPoint.Y = encodeJWKInt(big.NewInt(1))
+
+	testCases := []struct {
+		jwk    jwt.JWK
+		alg    string
+		signer jwt.Algorithm
+		err    error
+	}{
+		{rsaJWK, "RS256", jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey1)), nil},
+		{rsaJWK, "PS512", jwt.NewPS512(jwt.RSAPrivateKey(rsaPrivateKey1)), nil},
+		{rsaJWK, "", nil, jwt.ErrJWKUnsupported},
+		{rsaJWK, "ES256", nil, jwt.ErrJWKUnsupported},
+		{rs384JWK, "", jwt.NewRS384(jwt.RSAPrivateKey(rsaPrivateKey1)), nil},
+		{rs384JWK, "RS256", nil, jwt.ErrAlgValidation},
+		{es256JWK, "", jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1)), nil},
+		{es256JWK, "ES256", jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1)), nil},
+		{es256JWK, "ES384", nil, jwt.ErrAlgValidation},
+		{es384JWK, "", jwt.NewES384(jwt.ECDSAPrivateKey(es384PrivateKey1)), nil},
+		{invalidPoint, "", nil, jwt.ErrJWKInvalid},
+		{jwt.JWK{KeyType: "EC", Curve: "P-256"}, "", nil, jwt.ErrJWKInvalid},
+		{jwt.JWK{KeyType: "EC", Curve: "secp256k1"}, "", nil, jwt.ErrJWKUnsupported},
+		{jwt.JWK{KeyType: "oct"}, "HS256", nil, jwt.ErrJWKUnsupported},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.jwk.KeyType+tc.alg, func(t *testing.T) {
+			alg, err := tc.jwk.NewAlgorithm(tc.alg)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.JWK.NewAlgorithm err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if err != nil {
+				return
+			}
+			token, err := jwt.Sign(jwt.Payload{}, tc.signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pl jwt.Payload
+			if _, err = jwt.Verify(token, alg, &pl); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func encodeJWKInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func TestParseJWKSet(t *testing.T) {
+	rsaJWK := `{"kty":"RSA","kid":"rsa","alg":"PS256","n":"` + encodeJWKInt(rsaPublicKey1.N) +
+		`","e":"` + encodeJWKInt(big.NewInt(int64(rsaPublicKey1.E))) + `"}`
+	ecJWK := `{"kty":"EC","kid":"ec","crv":"P-256","x":"` + encodeJWKInt(es256PublicKey1.X) +
+		`","y":"` + encodeJWKInt(es256PublicKey1.Y) + `"}`
+	encJWK := `{"kty":"RSA","kid":"enc","use":"enc"}`
+
+	testCases := []struct {
+		data    string
+		signers map[string]jwt.Algorithm
+		err     error
+	}{
+		{
+			data: `{"keys":[` + rsaJWK + `,` + ecJWK + `,` + encJWK + `]}`,
+			signers: map[string]jwt.Algorithm{
+				"rsa": jwt.NewPS256(jwt.RSAPrivateKey(rsaPrivateKey1)),
+				"ec":  jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1)),
+			},
+		},
+		{data: `{"keys":[` + rsaJWK + `,` + rsaJWK + `]}`, err: jwt.ErrJWKInvalid},
+		{data: `{"keys":[{"kty":"oct","kid":"oct"}]}`, err: jwt.ErrJWKUnsupported},
+		{data: `{"keys":{}}`, err: jwt.ErrJWKInvalid},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.data, func(t *testing.T) {
+			algs, err := jwt.ParseJWKSet([]byte(tc.data))
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.ParseJWKSet err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := len(tc.signers), len(algs); got != want {
+				t.Fatalf("jwt.ParseJWKSet length mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			for kid, signer := range tc.signers {
+				token, err := jwt.Sign(jwt.Payload{}, signer)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var pl jwt.Payload
+				if _, err = jwt.Verify(token, algs[kid], &pl); err != nil {
+					t.Errorf("jwt.Verify with %q: %v", kid, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseJWK(t *testing.T) {
+	data := `{"kty":"EC","crv":"P-384","x":"` + encodeJWKInt(es384PublicKey1.X) +
+		`","y":"` + encodeJWKInt(es384PublicKey1.Y) + `"}`
+	alg, err := jwt.ParseJWK([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Sign(jwt.Payload{}, jwt.NewES384(jwt.ECDSAPrivateKey(es384PrivateKey1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pl jwt.Payload
+	if _, err = jwt.Verify(token, alg, &pl); err != nil {
+		t.Fatal(err)
+	}
+	_, err = jwt.ParseJWK([]byte(`{"kty":"RSA"`))
+	if want, got := jwt.ErrJWKInvalid, err; !internal.ErrorIs(got, want) {
+		t.Errorf("jwt.ParseJWK err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestThumbprint(t *testing.T) {
+	// Example from the RFC 7638, section 3.1.
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFF" +
+		"xuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2Qvz" +
+		"qY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPk" +
+		"sINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rfcKey := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	testCases := []struct {
+		name string
+		key  crypto.PublicKey
+		want string
+		err  error
+	}{
+		{"RFC 7638", rfcKey, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", nil},
+		{"unsupported key", ed25519PublicKey1, "", jwt.ErrJWKUnsupported},
+		{"unsupported curve", es256kPublicKey1, "", jwt.ErrJWKUnsupported},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := jwt.Thumbprint(tc.key)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.Thumbprint err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want := tc.want; got != want {
+				t.Errorf("jwt.Thumbprint mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestThumbprintKeyID(t *testing.T) {
+	for _, key := range []crypto.PublicKey{rsaPublicKey1, es256PublicKey1, es512PublicKey1} {
+		want, err := jwt.Thumbprint(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := jwt.Sign(jwt.Payload{}, jwt.NewHS256(hmacKey1), jwt.ThumbprintKeyID(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hd, err := jwt.Verify(token, jwt.NewHS256(hmacKey1), &jwt.Payload{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hd.KeyID; got != want || got == "" {
+			t.Errorf("jwt.ThumbprintKeyID mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	}
+}
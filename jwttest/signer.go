@@ -0,0 +1,78 @@
+// Package jwttest provides helpers for testing code that signs or verifies JWTs.
+//
+// WARNING: nothing in this package is secure. It must only be used in tests.
+package jwttest
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"sync"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// AlgorithmName is the "alg" header parameter of tokens signed by a Signer.
+// Since no real algorithm has this name, such tokens are rejected by jwt.Verify
+// unless a Signer is also used for verifying.
+const AlgorithmName = "INSECURE-TEST"
+
+var (
+	// ErrVerification is the error for a signature not created by a Signer.
+	ErrVerification = internal.WrapError("jwttest: verification failed", jwt.ErrSignatureMismatch)
+
+	_ jwt.Algorithm = new(Signer)
+)
+
+// Signer is an INSECURE algorithm for minting tokens in tests without setting up keys.
+// Signatures are a plain SHA-256 digest of the signing input, so anyone can forge them.
+//
+// It records everything it signs, and it is safe for concurrent use.
+type Signer struct {
+	mu     sync.Mutex
+	signed [][]byte
+}
+
+// NewSigner creates an INSECURE algorithm for tests.
+func NewSigner() *Signer {
+	return new(Signer)
+}
+
+// Name always returns AlgorithmName.
+func (*Signer) Name() string {
+	return AlgorithmName
+}
+
+// Sign returns the SHA-256 digest of headerPayload and records it.
+func (s *Signer) Sign(headerPayload []byte) ([]byte, error) {
+	s.mu.Lock()
+	s.signed = append(s.signed, append([]byte(nil), headerPayload...))
+	s.mu.Unlock()
+	sum := sha256.Sum256(headerPayload)
+	return sum[:], nil
+}
+
+// Size returns the signature's byte size.
+func (*Signer) Size() int {
+	return sha256.Size
+}
+
+// Verify succeeds for any signature created by any Signer.
+func (*Signer) Verify(headerPayload, sig []byte) (err error) {
+	if sig, err = internal.DecodeToBytes(sig); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(headerPayload)
+	if subtle.ConstantTimeCompare(sig, sum[:]) != 1 {
+		return ErrVerification
+	}
+	return nil
+}
+
+// Signed returns the signing inputs, that is, the encoded header and payload,
+// of all tokens signed so far, in order.
+func (s *Signer) Signed() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.signed...)
+}
@@ -0,0 +1,56 @@
+package jwttest_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/gbrlsnchs/jwt/v3/jwttest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSigner(t *testing.T) {
+	signer := jwttest.NewSigner()
+	pl := jwt.Payload{Subject: "someone"}
+	token, err := jwt.Sign(pl, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := jwt.Sign(pl, jwttest.NewSigner())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := token, again; !bytes.Equal(got, want) {
+		t.Errorf("jwt.Sign mismatch (-want +got):\n%s", cmp.Diff(string(want), string(got)))
+	}
+	if want, got := 1, len(signer.Signed()); got != want {
+		t.Errorf("jwttest.Signer.Signed length mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := token[:bytes.LastIndexByte(token, '.')], signer.Signed()[0]; !bytes.Equal(got, want) {
+		t.Errorf("jwttest.Signer.Signed mismatch (-want +got):\n%s", cmp.Diff(string(want), string(got)))
+	}
+
+	var got jwt.Payload
+	if _, err = jwt.Verify(token, jwttest.NewSigner(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := pl; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	// Real algorithms reject tokens signed by a Signer.
+	_, err = jwt.Verify(token, jwt.NewHS256([]byte("secret")), &got)
+	if want, got := jwt.ErrAlgValidation, err; !internal.ErrorIs(got, want) {
+		t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	other, err := jwt.Sign(jwt.Payload{Subject: "someone else"}, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := append(token[:bytes.LastIndexByte(token, '.')], other[bytes.LastIndexByte(other, '.'):]...)
+	_, err = jwt.Verify(tampered, jwttest.NewSigner(), &got)
+	if want, got := jwttest.ErrVerification, err; !internal.ErrorIs(got, want) {
+		t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,31 @@
+package jwtutil
+
+import (
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// AllowedAlgorithms returns a function that picks, among algs, the algorithm whose name matches
+// the "alg" header parameter, so tokens signed with any of them can be verified.
+// Tokens declaring any other algorithm are rejected with jwt.ErrAlgValidation.
+//
+// It can be used along with a Resolver:
+//
+//	rv := &jwtutil.Resolver{New: jwtutil.AllowedAlgorithms(rs256, es256)}
+//
+// Since the Resolver is not the "none" algorithm, unsecured tokens are always rejected by jwt.Verify.
+func AllowedAlgorithms(algs ...jwt.Algorithm) func(jwt.Header) (jwt.Algorithm, error) {
+	byName := make(map[string]jwt.Algorithm, len(algs))
+	for _, alg := range algs {
+		if _, ok := byName[alg.Name()]; !ok { // the first one takes precedence
+			byName[alg.Name()] = alg
+		}
+	}
+	return func(hd jwt.Header) (jwt.Algorithm, error) {
+		alg, ok := byName[hd.Algorithm]
+		if !ok {
+			return nil, internal.Errorf("jwtutil: %q is not allowed: %w", hd.Algorithm, jwt.ErrAlgValidation)
+		}
+		return alg, nil
+	}
+}
@@ -0,0 +1,47 @@
+package jwtutil_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/gbrlsnchs/jwt/v3/jwtutil"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAllowedAlgorithms(t *testing.T) {
+	var (
+		rs256 = jwt.NewRS256(jwt.RSAPrivateKey(rsaKey))
+		es256 = jwt.NewES256(jwt.ECDSAPrivateKey(ecKey))
+		none  = []byte(base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + ".e30.")
+	)
+	testCases := []struct {
+		signer jwt.Algorithm
+		token  []byte
+		err    error
+	}{
+		{signer: rs256, err: nil},
+		{signer: es256, err: nil},
+		{signer: jwt.NewRS512(jwt.RSAPrivateKey(rsaKey)), err: jwt.ErrAlgValidation},
+		{signer: jwt.NewHS256([]byte("secret")), err: jwt.ErrAlgValidation},
+		{token: none, err: jwt.ErrAlgNone},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			token := tc.token
+			if tc.signer != nil {
+				var err error
+				if token, err = jwt.Sign(jwt.Payload{}, tc.signer); err != nil {
+					t.Fatal(err)
+				}
+			}
+			rv := &jwtutil.Resolver{New: jwtutil.AllowedAlgorithms(rs256, es256)}
+			var pl jwt.Payload
+			_, err := jwt.Verify(token, rv, &pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
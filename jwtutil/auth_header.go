@@ -0,0 +1,37 @@
+package jwtutil
+
+import (
+	"strings"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// ErrAuthHeader is the error for when an Authorization header doesn't hold a bearer token.
+var ErrAuthHeader = internal.NewError("invalid Authorization header")
+
+// FromAuthHeader extracts a token from the value of an Authorization header that uses
+// the "Bearer" scheme, as per the RFC 6750, so it can be passed to jwt.Verify:
+//
+//	token, err := jwtutil.FromAuthHeader(r.Header.Get("Authorization"))
+//
+// The scheme is matched case-insensitively and any spaces around the token are trimmed.
+// Empty headers, other schemes and tokens with spaces in them result in ErrAuthHeader.
+func FromAuthHeader(header string) ([]byte, error) {
+	const scheme = "bearer"
+	header = strings.TrimSpace(header)
+	if len(header) <= len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) {
+		return nil, internal.Errorf("jwtutil: not a bearer token: %w", ErrAuthHeader)
+	}
+	token := header[len(scheme):]
+	if token[0] != ' ' && token[0] != '\t' {
+		return nil, internal.Errorf("jwtutil: not a bearer token: %w", ErrAuthHeader)
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, internal.Errorf("jwtutil: missing token: %w", ErrAuthHeader)
+	}
+	if strings.ContainsAny(token, " \t") {
+		return nil, internal.Errorf("jwtutil: token has spaces: %w", ErrAuthHeader)
+	}
+	return []byte(token), nil
+}
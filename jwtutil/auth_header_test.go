@@ -0,0 +1,40 @@
+package jwtutil_test
+
+import (
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/gbrlsnchs/jwt/v3/jwtutil"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFromAuthHeader(t *testing.T) {
+	testCases := []struct {
+		header string
+		want   string
+		err    error
+	}{
+		{"Bearer a.b.c", "a.b.c", nil},
+		{"bearer a.b.c", "a.b.c", nil},
+		{"BEARER   a.b.c  ", "a.b.c", nil},
+		{" Bearer\ta.b.c", "a.b.c", nil},
+		{"", "", jwtutil.ErrAuthHeader},
+		{"Bearer", "", jwtutil.ErrAuthHeader},
+		{"Bearer   ", "", jwtutil.ErrAuthHeader},
+		{"Bearera.b.c", "", jwtutil.ErrAuthHeader},
+		{"Basic dXNlcjpwYXNz", "", jwtutil.ErrAuthHeader},
+		{"Bearer a.b.c d", "", jwtutil.ErrAuthHeader},
+		{"a.b.c", "", jwtutil.ErrAuthHeader},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			token, err := jwtutil.FromAuthHeader(tc.header)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwtutil.FromAuthHeader err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.want, string(token); got != want {
+				t.Errorf("jwtutil.FromAuthHeader mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
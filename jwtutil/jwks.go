@@ -0,0 +1,255 @@
+package jwtutil
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+const (
+	// DefaultMinRefreshInterval is the default minimum interval between refreshes caused by cache misses.
+	DefaultMinRefreshInterval = time.Minute
+
+	maxJWKSetSize = 1 << 20
+)
+
+var (
+	// ErrKeyNotFound is the error for when no key matches a JOSE header.
+	ErrKeyNotFound = internal.NewError("key not found")
+	// ErrJWKSFetch is the error for when a JWK Set can't be fetched.
+	ErrJWKSFetch = internal.NewError("failed to fetch JWK Set")
+)
+
+// JWKS is a cache of keys from a JWK Set, usually published by an issuer on a "jwks_uri" endpoint.
+// It is safe for concurrent use.
+//
+// Its Algorithm method resolves verifying algorithms by the "kid" and "alg" parameters,
+// so it can be used along with a Resolver:
+//
+//	rv := &jwtutil.Resolver{New: jwks.Algorithm}
+//
+// When verifying with jwt.VerifyContext, AlgorithmContext makes fetches cancellable:
+//
+//	rv := &jwtutil.Resolver{NewContext: jwks.AlgorithmContext}
+type JWKS struct {
+	// URL is the address the JWK Set is fetched from.
+	URL string
+	// Client is the HTTP client used for fetching the JWK Set.
+	// If nil, http.DefaultClient is used.
+	Client *http.Client
+	// RefreshInterval sets how long keys are cached before being fetched again.
+	// If zero, keys are only fetched again when a key can't be found.
+	RefreshInterval time.Duration
+	// MinRefreshInterval sets the minimum interval between fetches caused by a key not being found.
+	// If zero, DefaultMinRefreshInterval is used.
+	MinRefreshInterval time.Duration
+	// MinRSAKeySize sets the minimum bit length of RSA keys, for example, for issuers with legacy keys.
+	// If zero, jwt.DefaultMinRSAKeySize is used.
+	MinRSAKeySize int
+
+	refreshMu sync.Mutex
+
+	mu        sync.RWMutex
+	keys      map[string]jwt.JWK
+	noKID     []jwt.JWK
+	algs      map[string]jwt.Algorithm
+	gen       uint64 // incremented whenever the keys are replaced
+	fetchedAt time.Time
+}
+
+// Parse replaces the cached keys with the ones from a JWK Set document.
+func (ks *JWKS) Parse(data []byte) error {
+	var set jwt.JWKSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return err
+	}
+	var (
+		keys  = make(map[string]jwt.JWK, len(set.Keys))
+		noKID []jwt.JWK
+	)
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if jwk.KeyID == "" {
+			noKID = append(noKID, jwk)
+			continue
+		}
+		keys[jwk.KeyID] = jwk
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys = keys
+	ks.noKID = noKID
+	ks.algs = make(map[string]jwt.Algorithm)
+	ks.gen++
+	return nil
+}
+
+// Refresh fetches the JWK Set from URL and replaces the cached keys.
+func (ks *JWKS) Refresh() error {
+	return ks.RefreshContext(context.Background())
+}
+
+// RefreshContext is like Refresh, but the request for fetching the JWK Set uses ctx.
+func (ks *JWKS) RefreshContext(ctx context.Context) error {
+	ks.refreshMu.Lock()
+	defer ks.refreshMu.Unlock()
+	return ks.refresh(ctx)
+}
+
+// Algorithm returns an algorithm for verifying a JWT whose header is hd.
+// Keys are fetched if they are stale or if no key matches the "kid" parameter.
+//
+// An empty "kid" is accepted when the JWK Set contains a single key. Otherwise, it matches all keys
+// without a "kid" that support the "alg" parameter, which are tried in turn by a jwt.KeySet.
+func (ks *JWKS) Algorithm(hd jwt.Header) (jwt.Algorithm, error) {
+	return ks.AlgorithmContext(context.Background(), hd)
+}
+
+// AlgorithmContext is like Algorithm, but requests for fetching keys use ctx.
+func (ks *JWKS) AlgorithmContext(ctx context.Context, hd jwt.Header) (jwt.Algorithm, error) {
+	if ks.stale() {
+		if err := ks.refreshIf(ctx, ks.stale); err != nil {
+			return nil, err
+		}
+	}
+	alg, err := ks.algorithm(hd)
+	if err != ErrKeyNotFound {
+		return alg, err
+	}
+	if err = ks.refreshIf(ctx, ks.missed); err != nil {
+		return nil, err
+	}
+	return ks.algorithm(hd)
+}
+
+func (ks *JWKS) algorithm(hd jwt.Header) (jwt.Algorithm, error) {
+	cacheKey := hd.KeyID + "\x00" + hd.Algorithm
+	ks.mu.RLock()
+	alg, ok := ks.algs[cacheKey]
+	jwks := ks.candidates(hd.KeyID)
+	gen := ks.gen
+	ks.mu.RUnlock()
+	if ok {
+		return alg, nil
+	}
+	if len(jwks) == 0 {
+		return nil, ErrKeyNotFound
+	}
+	var opts []func(*jwt.RSASHA)
+	if ks.MinRSAKeySize > 0 {
+		opts = append(opts, jwt.RSAMinKeySize(ks.MinRSAKeySize))
+	}
+	alg, err := newAlgorithm(jwks, hd.Algorithm, opts)
+	if err != nil {
+		return nil, err
+	}
+	ks.mu.Lock()
+	// Don't cache an algorithm built from keys that have been replaced in the meantime.
+	if ks.algs != nil && ks.gen == gen {
+		ks.algs[cacheKey] = alg
+	}
+	ks.mu.Unlock()
+	return alg, nil
+}
+
+// candidates returns the keys that may match kid. It must be called with mu held.
+func (ks *JWKS) candidates(kid string) []jwt.JWK {
+	if jwk, ok := ks.keys[kid]; ok {
+		return []jwt.JWK{jwk}
+	}
+	if kid != "" {
+		return nil
+	}
+	if len(ks.keys) == 1 && len(ks.noKID) == 0 {
+		for _, jwk := range ks.keys {
+			return []jwt.JWK{jwk}
+		}
+	}
+	return ks.noKID
+}
+
+// newAlgorithm creates an algorithm for a single key or a jwt.KeySet for several ones,
+// skipping keys that don't support alg.
+func newAlgorithm(jwks []jwt.JWK, alg string, opts []func(*jwt.RSASHA)) (jwt.Algorithm, error) {
+	if len(jwks) == 1 {
+		return jwks[0].NewAlgorithm(alg, opts...)
+	}
+	algs := make([]jwt.Algorithm, 0, len(jwks))
+	for _, jwk := range jwks {
+		if a, err := jwk.NewAlgorithm(alg, opts...); err == nil {
+			algs = append(algs, a)
+		}
+	}
+	if len(algs) == 0 {
+		return nil, ErrKeyNotFound
+	}
+	return jwt.NewKeySet(algs...), nil
+}
+
+func (ks *JWKS) refreshIf(ctx context.Context, cond func() bool) error {
+	ks.refreshMu.Lock()
+	defer ks.refreshMu.Unlock()
+	if !cond() { // another goroutine has just refreshed
+		return nil
+	}
+	return ks.refresh(ctx)
+}
+
+func (ks *JWKS) refresh(ctx context.Context) error {
+	client := ks.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, ks.URL, nil)
+	if err != nil {
+		return internal.Errorf("jwtutil: %v: %w", err, ErrJWKSFetch)
+	}
+	ks.mu.Lock()
+	ks.fetchedAt = time.Now()
+	ks.mu.Unlock()
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return internal.Errorf("jwtutil: %v: %w", err, ErrJWKSFetch)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return internal.Errorf("jwtutil: unexpected status %q: %w", resp.Status, ErrJWKSFetch)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxJWKSetSize))
+	if err != nil {
+		return internal.Errorf("jwtutil: %v: %w", err, ErrJWKSFetch)
+	}
+	return ks.Parse(data)
+}
+
+func (ks *JWKS) stale() bool {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	if ks.fetchedAt.IsZero() {
+		return ks.keys == nil
+	}
+	return ks.RefreshInterval > 0 && time.Since(ks.fetchedAt) >= ks.RefreshInterval
+}
+
+func (ks *JWKS) missed() bool {
+	minInterval := ks.MinRefreshInterval
+	if minInterval == 0 {
+		minInterval = DefaultMinRefreshInterval
+	}
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.URL != "" && (ks.fetchedAt.IsZero() || time.Since(ks.fetchedAt) >= minInterval)
+}
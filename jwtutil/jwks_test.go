@@ -0,0 +1,249 @@
+package jwtutil_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/gbrlsnchs/jwt/v3/jwtutil"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	rsaKey, _   = rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _    = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	es384Key, _ = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	jwkSet = jwt.JWKSet{Keys: []jwt.JWK{
+		{
+			KeyType: "RSA",
+			KeyID:   "rsa",
+			N:       encodeInt(rsaKey.N),
+			E:       encodeInt(big.NewInt(int64(rsaKey.E))),
+		},
+		{
+			KeyType: "EC",
+			KeyID:   "ec",
+			Curve:   "P-256",
+			X:       encodeInt(ecKey.X),
+			Y:       encodeInt(ecKey.Y),
+		},
+	}}
+)
+
+func TestJWKS(t *testing.T) {
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_ = json.NewEncoder(w).Encode(jwkSet)
+	}))
+	defer srv.Close()
+
+	jwks := &jwtutil.JWKS{URL: srv.URL}
+	testCases := []struct {
+		signer      jwt.Algorithm
+		kid         string
+		err         error
+		wantFetches int32
+	}{
+		{jwt.NewRS256(jwt.RSAPrivateKey(rsaKey)), "rsa", nil, 1},
+		{jwt.NewPS384(jwt.RSAPrivateKey(rsaKey)), "rsa", nil, 1},
+		{jwt.NewES256(jwt.ECDSAPrivateKey(ecKey)), "ec", nil, 1},
+		{jwt.NewRS256(jwt.RSAPrivateKey(rsaKey)), "ec", jwt.ErrAlgValidation, 1},
+		{jwt.NewRS256(jwt.RSAPrivateKey(rsaKey)), "unknown", jwtutil.ErrKeyNotFound, 1}, // too soon to refresh again
+		{jwt.NewRS256(jwt.RSAPrivateKey(rsaKey)), "", jwtutil.ErrKeyNotFound, 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.kid, func(t *testing.T) {
+			token, err := jwt.Sign(jwt.Payload{}, tc.signer, jwt.KeyID(tc.kid))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pl jwt.Payload
+			_, err = jwt.Verify(token, &jwtutil.Resolver{New: jwks.Algorithm}, &pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantFetches, atomic.LoadInt32(&fetches); got != want {
+				t.Errorf("fetch count mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestJWKSRefreshOnMiss(t *testing.T) {
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set := jwt.JWKSet{Keys: jwkSet.Keys[:1]}
+		if atomic.AddInt32(&fetches, 1) > 1 { // simulate rotation
+			set.Keys = jwkSet.Keys[1:]
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	jwks := &jwtutil.JWKS{URL: srv.URL, MinRefreshInterval: time.Nanosecond}
+	if _, err := jwks.Algorithm(jwt.Header{KeyID: "rsa", Algorithm: "RS256"}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := jwks.Algorithm(jwt.Header{KeyID: "ec", Algorithm: "ES256"}); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := int32(2), atomic.LoadInt32(&fetches); got != want {
+		t.Errorf("fetch count mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestJWKSParse(t *testing.T) {
+	data, err := json.Marshal(jwt.JWKSet{Keys: jwkSet.Keys[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jwks jwtutil.JWKS
+	if err = jwks.Parse(data); err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Sign(jwt.Payload{}, jwt.NewRS512(jwt.RSAPrivateKey(rsaKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pl jwt.Payload
+	if _, err = jwt.Verify(token, &jwtutil.Resolver{New: jwks.Algorithm}, &pl); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJWKSMinRSAKeySize(t *testing.T) {
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(jwt.JWKSet{Keys: []jwt.JWK{{
+		KeyType: "RSA",
+		KeyID:   "small",
+		N:       encodeInt(small.N),
+		E:       encodeInt(big.NewInt(int64(small.E))),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hd := jwt.Header{KeyID: "small", Algorithm: "RS256"}
+	testCases := []struct {
+		minKeySize int
+		err        error
+	}{
+		{0, jwt.ErrRSAKeyTooSmall},
+		{1024, nil},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			jwks := jwtutil.JWKS{MinRSAKeySize: tc.minKeySize}
+			if err := jwks.Parse(data); err != nil {
+				t.Fatal(err)
+			}
+			_, err := jwks.Algorithm(hd)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwtutil.JWKS.Algorithm err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestJWKSWithoutKeyID(t *testing.T) {
+	rsaKey2, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := jwt.JWKSet{Keys: []jwt.JWK{
+		{KeyType: "RSA", N: encodeInt(rsaKey.N), E: encodeInt(big.NewInt(int64(rsaKey.E)))},
+		{KeyType: "RSA", N: encodeInt(rsaKey2.N), E: encodeInt(big.NewInt(int64(rsaKey2.E)))},
+		{KeyType: "EC", Curve: "P-256", X: encodeInt(ecKey.X), Y: encodeInt(ecKey.Y)},
+	}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jwks jwtutil.JWKS
+	if err = jwks.Parse(data); err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		signer jwt.Algorithm
+		err    error
+	}{
+		{"first RSA key", jwt.NewRS256(jwt.RSAPrivateKey(rsaKey)), nil},
+		{"second RSA key", jwt.NewPS256(jwt.RSAPrivateKey(rsaKey2)), nil},
+		{"EC key", jwt.NewES256(jwt.ECDSAPrivateKey(ecKey)), nil},
+		{"unknown EC key", jwt.NewES256(jwt.ECDSAPrivateKey(otherKey)), jwt.ErrSignatureMismatch},
+		{"unsupported algorithm", jwt.NewES384(jwt.ECDSAPrivateKey(es384Key)), jwtutil.ErrKeyNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := jwt.Sign(jwt.Payload{}, tc.signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pl jwt.Payload
+			_, err = jwt.Verify(token, &jwtutil.Resolver{New: jwks.Algorithm}, &pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestJWKSFetchError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	jwks := &jwtutil.JWKS{URL: srv.URL}
+	_, err := jwks.Algorithm(jwt.Header{KeyID: "rsa", Algorithm: "RS256"})
+	if want, got := jwtutil.ErrJWKSFetch, err; !internal.ErrorIs(got, want) {
+		t.Errorf("jwtutil.JWKS.Algorithm err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestJWKSContext(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done // hang until the test is over
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	token, err := jwt.Sign(jwt.Payload{}, jwt.NewRS256(jwt.RSAPrivateKey(rsaKey)), jwt.KeyID("rsa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := &jwtutil.JWKS{URL: srv.URL}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var pl jwt.Payload
+	_, err = jwt.VerifyContext(ctx, token, &jwtutil.Resolver{NewContext: jwks.AlgorithmContext}, &pl)
+	if want, got := context.DeadlineExceeded, err; got != want {
+		t.Errorf("jwt.VerifyContext err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func encodeInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
@@ -1,14 +1,21 @@
 package jwtutil
 
 import (
+	"context"
+
 	"github.com/gbrlsnchs/jwt/v3"
 	"github.com/gbrlsnchs/jwt/v3/internal"
 )
 
 // Resolver is an Algorithm resolver.
+// It keeps the first algorithm it resolves, so it's not safe for concurrent use
+// and a new Resolver must be used for each token.
 type Resolver struct {
 	New func(jwt.Header) (jwt.Algorithm, error)
-	alg jwt.Algorithm
+	// NewContext is like New, but receives the context passed to jwt.VerifyContext.
+	// If set, it's preferred over New when verifying with a context.
+	NewContext func(context.Context, jwt.Header) (jwt.Algorithm, error)
+	alg        jwt.Algorithm
 }
 
 // ErrNilAlg is the error for when an algorithm can't be resolved.
@@ -27,10 +34,26 @@ func (rv *Resolver) Resolve(hd jwt.Header) error {
 	if rv.alg != nil {
 		return nil
 	}
-	if rv.New == nil {
+	return rv.ResolveContext(context.Background(), hd)
+}
+
+// ResolveContext sets an Algorithm based on a JOSE Header, passing ctx to NewContext.
+func (rv *Resolver) ResolveContext(ctx context.Context, hd jwt.Header) error {
+	if rv.alg != nil {
+		return nil
+	}
+	var (
+		alg jwt.Algorithm
+		err error
+	)
+	switch {
+	case rv.NewContext != nil:
+		alg, err = rv.NewContext(ctx, hd)
+	case rv.New != nil:
+		alg, err = rv.New(hd)
+	default:
 		return ErrNilAlg
 	}
-	alg, err := rv.New(hd)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,102 @@
+package jwtutil
+
+import (
+	"sync"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// KeyRotator signs tokens with a primary key and verifies tokens signed with either
+// the primary or a secondary key, told apart by the "kid" header parameter.
+// It is safe for concurrent use.
+//
+// A key is rotated in two steps, so no token is ever rejected:
+//
+//	kr.Stage("2020-02", newAlg) // verifiers accept the new key, tokens are still signed with the old one
+//	kr.Promote()                // tokens are signed with the new key, the old one is only used for verifying
+//
+// Once tokens signed with the old key have expired, it can be dropped with Retire,
+// or replaced by staging the next key.
+type KeyRotator struct {
+	mu        sync.RWMutex
+	primary   rotatedKey
+	secondary rotatedKey
+}
+
+type rotatedKey struct {
+	kid string
+	alg jwt.Algorithm
+}
+
+// NewKeyRotator creates a KeyRotator whose primary key is alg, identified by kid.
+// It panics if kid is empty or alg is nil.
+func NewKeyRotator(kid string, alg jwt.Algorithm) *KeyRotator {
+	checkRotatedKey(kid, alg)
+	return &KeyRotator{primary: rotatedKey{kid, alg}}
+}
+
+// Stage sets alg, identified by kid, as the secondary key, replacing the current one, if any.
+// It panics if kid is empty or already used by the primary key, or if alg is nil.
+func (kr *KeyRotator) Stage(kid string, alg jwt.Algorithm) {
+	checkRotatedKey(kid, alg)
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if kid == kr.primary.kid {
+		panic(internal.Errorf("jwtutil: %q is the primary key ID: %w", kid, jwt.ErrKeySetInvalid))
+	}
+	kr.secondary = rotatedKey{kid, alg}
+}
+
+// Promote swaps the primary and the secondary keys, so new tokens are signed with the staged key
+// while the former primary key is still used for verifying. It does nothing if no key is staged.
+func (kr *KeyRotator) Promote() {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if kr.secondary.alg != nil {
+		kr.primary, kr.secondary = kr.secondary, kr.primary
+	}
+}
+
+// Retire drops the secondary key, so tokens signed with it are not accepted anymore.
+func (kr *KeyRotator) Retire() {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	kr.secondary = rotatedKey{}
+}
+
+// KeyID returns the key ID of the primary key.
+func (kr *KeyRotator) KeyID() string {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return kr.primary.kid
+}
+
+// Sign signs payload with the primary key, setting the "kid" header parameter to its key ID.
+func (kr *KeyRotator) Sign(payload interface{}, opts ...jwt.SignOption) ([]byte, error) {
+	kr.mu.RLock()
+	primary := kr.primary
+	kr.mu.RUnlock()
+	return jwt.Sign(payload, primary.alg, append(opts[:len(opts):len(opts)], jwt.KeyID(primary.kid))...)
+}
+
+// Algorithm returns the key whose key ID matches the "kid" header parameter, so it can be used
+// along with a Resolver:
+//
+//	rv := &jwtutil.Resolver{New: kr.Algorithm}
+func (kr *KeyRotator) Algorithm(hd jwt.Header) (jwt.Algorithm, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	for _, key := range []rotatedKey{kr.primary, kr.secondary} {
+		if key.alg != nil && key.kid == hd.KeyID {
+			return key.alg, nil
+		}
+	}
+	return nil, internal.Errorf("jwtutil: %q: %w", hd.KeyID, ErrKeyNotFound)
+}
+
+func checkRotatedKey(kid string, alg jwt.Algorithm) {
+	if kid == "" || alg == nil {
+		panic(internal.Errorf("jwtutil: missing key ID or algorithm: %w", jwt.ErrKeySetInvalid))
+	}
+}
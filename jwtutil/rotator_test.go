@@ -0,0 +1,82 @@
+package jwtutil_test
+
+import (
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/gbrlsnchs/jwt/v3/jwtutil"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKeyRotator(t *testing.T) {
+	oldAlg := jwt.NewHS256([]byte("old secret"))
+	newAlg := jwt.NewHS256([]byte("new secret"))
+	kr := jwtutil.NewKeyRotator("old", oldAlg)
+
+	sign := func(t *testing.T) []byte {
+		token, err := kr.Sign(jwt.Payload{}, jwt.KeyID("ignored"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	verify := func(token []byte) (jwt.Header, error) {
+		var pl jwt.Payload
+		return jwt.Verify(token, &jwtutil.Resolver{New: kr.Algorithm}, &pl)
+	}
+	checkKeyID := func(t *testing.T, token []byte, want string) {
+		hd, err := verify(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hd.KeyID; got != want {
+			t.Errorf("jwtutil.KeyRotator.Sign kid mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	}
+
+	oldToken := sign(t)
+	checkKeyID(t, oldToken, "old")
+
+	kr.Stage("new", newAlg)
+	checkKeyID(t, sign(t), "old")
+
+	kr.Promote()
+	if want, got := "new", kr.KeyID(); got != want {
+		t.Errorf("jwtutil.KeyRotator.KeyID mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	newToken := sign(t)
+	checkKeyID(t, newToken, "new")
+	checkKeyID(t, oldToken, "old")
+
+	kr.Retire()
+	checkKeyID(t, newToken, "new")
+	if _, err := verify(oldToken); !internal.ErrorIs(err, jwtutil.ErrKeyNotFound) {
+		t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(jwtutil.ErrKeyNotFound, err))
+	}
+	kr.Promote() // nothing staged
+	checkKeyID(t, sign(t), "new")
+}
+
+func TestKeyRotatorInvalid(t *testing.T) {
+	kr := jwtutil.NewKeyRotator("kid", jwt.NewHS256([]byte("secret")))
+	testCases := []struct {
+		name string
+		fn   func()
+	}{
+		{"empty key ID", func() { jwtutil.NewKeyRotator("", jwt.NewHS256([]byte("secret"))) }},
+		{"nil algorithm", func() { jwtutil.NewKeyRotator("kid", nil) }},
+		{"primary key ID", func() { kr.Stage("kid", jwt.NewHS256([]byte("other"))) }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if want, got := jwt.ErrKeySetInvalid, err; !internal.ErrorIs(got, want) {
+					t.Errorf("err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+				}
+			}()
+			tc.fn()
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package jwt
+
+import "github.com/gbrlsnchs/jwt/v3/internal"
+
+var (
+	// ErrKeySetInvalid is the error for creating a KeySet without algorithms, with mismatching ones
+	// or with the "none" algorithm.
+	ErrKeySetInvalid = internal.NewError("jwt: key set is invalid")
+
+	_ Algorithm = new(KeySet)
+)
+
+// KeySet is an algorithm that verifies signatures with several keys of the same algorithm,
+// for example, old and new HMAC secrets while they are being rotated, or the public keys
+// of an issuer that doesn't set the "kid" header parameter.
+//
+// Signing uses the first algorithm. Verifying tries every algorithm, in order, and succeeds
+// if any of them succeeds. All algorithms are always tried, so the time spent
+// doesn't depend on which key matches.
+type KeySet struct {
+	// OnMatch, if set, is called with the index of the algorithm that verified a signature.
+	// It can be used for telling when an old key is not in use anymore.
+	// Like KeySet itself, it may be called concurrently.
+	OnMatch func(i int)
+
+	algs []Algorithm
+}
+
+// NewKeySet creates a KeySet with algs, which must all have the same name, other than "none".
+// Since Verify checks the "alg" header parameter against that name, tokens signed
+// with other algorithms are rejected without any key being tried.
+func NewKeySet(algs ...Algorithm) *KeySet {
+	if len(algs) == 0 || algs[0].Name() == None().Name() {
+		panic(ErrKeySetInvalid)
+	}
+	for _, alg := range algs[1:] {
+		if alg.Name() != algs[0].Name() {
+			panic(ErrKeySetInvalid)
+		}
+	}
+	return &KeySet{algs: algs}
+}
+
+// Name returns the name shared by the algorithms.
+func (ks *KeySet) Name() string {
+	return ks.algs[0].Name()
+}
+
+// Sign signs headerPayload using the first algorithm.
+func (ks *KeySet) Sign(headerPayload []byte) ([]byte, error) {
+	return ks.algs[0].Sign(headerPayload)
+}
+
+// Size returns the first algorithm's signature byte size.
+func (ks *KeySet) Size() int {
+	return ks.algs[0].Size()
+}
+
+// Verify verifies a signature based on headerPayload using every algorithm.
+// If none of them succeeds, the error from the first one is returned.
+func (ks *KeySet) Verify(headerPayload, sig []byte) error {
+	var (
+		match    = -1
+		firstErr error
+	)
+	for i, alg := range ks.algs {
+		err := alg.Verify(headerPayload, sig)
+		switch {
+		case err == nil && match < 0:
+			match = i
+		case err != nil && i == 0:
+			firstErr = err
+		}
+	}
+	if match < 0 {
+		return firstErr
+	}
+	if ks.OnMatch != nil {
+		ks.OnMatch(match)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKeySet(t *testing.T) {
+	testCases := []struct {
+		name      string
+		signer    jwt.Algorithm
+		verifiers []jwt.Algorithm
+		wantMatch int
+		err       error
+	}{
+		{
+			name:      "new key",
+			signer:    jwt.NewHS256(hmacKey1),
+			verifiers: []jwt.Algorithm{jwt.NewHS256(hmacKey1), jwt.NewHS256(hmacKey2)},
+			wantMatch: 0,
+		},
+		{
+			name:      "old key",
+			signer:    jwt.NewHS256(hmacKey2),
+			verifiers: []jwt.Algorithm{jwt.NewHS256(hmacKey1), jwt.NewHS256(hmacKey2)},
+			wantMatch: 1,
+		},
+		{
+			name:      "unknown key",
+			signer:    jwt.NewHS256([]byte("unknown")),
+			verifiers: []jwt.Algorithm{jwt.NewHS256(hmacKey1), jwt.NewHS256(hmacKey2)},
+			wantMatch: -1,
+			err:       jwt.ErrHMACVerification,
+		},
+		{
+			name:   "RSA",
+			signer: jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey2)),
+			verifiers: []jwt.Algorithm{
+				jwt.NewRS256(jwt.RSAPublicKey(rsaPublicKey1)),
+				jwt.NewRS256(jwt.RSAPublicKey(rsaPublicKey2)),
+			},
+			wantMatch: 1,
+		},
+		{
+			name:   "RSA unknown key",
+			signer: jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey2)),
+			verifiers: []jwt.Algorithm{
+				jwt.NewRS256(jwt.RSAPublicKey(rsaPublicKey1)),
+			},
+			wantMatch: -1,
+			err:       jwt.ErrRSAVerification,
+		},
+		{
+			name:   "ECDSA",
+			signer: jwt.NewES384(jwt.ECDSAPrivateKey(es384PrivateKey1)),
+			verifiers: []jwt.Algorithm{
+				jwt.NewES384(jwt.ECDSAPublicKey(es384PublicKey1)),
+				jwt.NewES384(jwt.ECDSAPublicKey(es384PublicKey2)),
+			},
+			wantMatch: 0,
+		},
+		{
+			name:   "ECDSA other algorithm",
+			signer: jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1)),
+			verifiers: []jwt.Algorithm{
+				jwt.NewES384(jwt.ECDSAPublicKey(es384PublicKey1)),
+			},
+			wantMatch: -1,
+			err:       jwt.ErrAlgValidation,
+		},
+		{
+			name:      "unsecured",
+			signer:    jwt.None(),
+			verifiers: []jwt.Algorithm{jwt.NewHS256(hmacKey1)},
+			wantMatch: -1,
+			err:       jwt.ErrAlgNone,
+		},
+		{
+			name:      "other algorithm",
+			signer:    jwt.NewHS384(hmacKey1),
+			verifiers: []jwt.Algorithm{jwt.NewHS256(hmacKey1)},
+			wantMatch: -1,
+			err:       jwt.ErrAlgValidation,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := jwt.Sign(tp, tc.signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			match := -1
+			ks := jwt.NewKeySet(tc.verifiers...)
+			ks.OnMatch = func(i int) { match = i }
+			var pl testPayload
+			_, err = jwt.Verify(token, ks, &pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantMatch, match; got != want {
+				t.Errorf("jwt.KeySet.OnMatch mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestNewKeySetPanic(t *testing.T) {
+	testCases := []struct {
+		name string
+		algs []jwt.Algorithm
+	}{
+		{"empty", nil},
+		{"none", []jwt.Algorithm{jwt.None()}},
+		{"mismatch", []jwt.Algorithm{jwt.NewHS256(hmacKey1), jwt.NewHS512(hmacKey2)}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if want, got := jwt.ErrKeySetInvalid, recover(); got != want {
+					t.Errorf("jwt.NewKeySet panic mismatch (-want +got):\n%s", cmp.Diff(want, got))
+				}
+			}()
+			jwt.NewKeySet(tc.algs...)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package jwt
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// ErrNotNested is the error for when a JWT's "cty" header parameter doesn't declare a nested JWT.
+var ErrNotNested = internal.NewError(`jwt: "cty" is not "JWT"`)
+
+// SignNested signs a JWT with alg, wrapping it in another JWT, as per the RFC 7519.
+// The outer JWT's "cty" header parameter is set to "JWT".
+func SignNested(token []byte, alg Algorithm, opts ...SignOption) ([]byte, error) {
+	hd, err := signHeader(alg, opts)
+	if err != nil {
+		return nil, err
+	}
+	hd.ContentType = "JWT"
+	hb, err := marshalHeader(hd)
+	if err != nil {
+		return nil, err
+	}
+	return encodeAndSign(hb, token, alg)
+}
+
+// VerifyNested verifies a nested JWT. The outer JWT's signature is verified using outer,
+// then the inner JWT, which is the outer JWT's payload, is verified by Verify using inner
+// and decoded into payload. Options, including validators, are only run for the inner JWT.
+//
+// It returns the headers of both JWTs. If the outer JWT's "cty" header parameter is not "JWT",
+// ErrNotNested is returned, and if its payload is not a JWT, ErrMalformed is.
+func VerifyNested(token []byte, outer, inner Algorithm, payload interface{}, opts ...VerifyOption) (outerHd, innerHd Header, err error) {
+	rt, err := verifySignature(context.Background(), token, outer, nil)
+	if err != nil {
+		return rt.hd, innerHd, err
+	}
+	if !strings.EqualFold(rt.hd.ContentType, "JWT") {
+		return rt.hd, innerHd, internal.Errorf("jwt: got %q: %w", rt.hd.ContentType, ErrNotNested)
+	}
+	nested, err := internal.DecodeToBytes(rt.payload())
+	if err != nil {
+		return rt.hd, innerHd, malformed(err)
+	}
+	innerHd, err = Verify(nested, inner, payload, opts...)
+	return rt.hd, innerHd, err
+}
@@ -0,0 +1,128 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNested(t *testing.T) {
+	var (
+		innerAlg = jwt.NewHS256(hmacKey1)
+		outerAlg = jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1))
+	)
+	innerToken, err := jwt.Sign(tp, innerAlg, jwt.KeyID("inner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested, err := jwt.SignNested(innerToken, outerAlg, jwt.KeyID("outer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	notNested, err := jwt.Sign(tp, outerAlg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notJWT, err := jwt.SignNested([]byte("not a JWT"), outerAlg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notBase64 := []byte(jwt.EncodeSegment([]byte(`{"alg":"ES256","cty":"JWT","typ":"JWT"}`)) + ".!!!")
+	sig, err := outerAlg.Sign(notBase64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notBase64 = append(notBase64, "."+jwt.EncodeSegment(sig)...)
+
+	testCases := []struct {
+		name         string
+		token        []byte
+		outer, inner jwt.Algorithm
+		vds          []jwt.Validator
+		wantOuter    jwt.Header
+		wantInner    jwt.Header
+		wantPayload  testPayload
+		err          error
+	}{
+		{
+			name:        "valid",
+			token:       nested,
+			outer:       outerAlg,
+			inner:       innerAlg,
+			wantOuter:   jwt.Header{Algorithm: "ES256", ContentType: "JWT", KeyID: "outer", Type: "JWT"},
+			wantInner:   jwt.Header{Algorithm: "HS256", KeyID: "inner", Type: "JWT"},
+			wantPayload: tp,
+		},
+		{
+			name:      "validators",
+			token:     nested,
+			outer:     outerAlg,
+			inner:     innerAlg,
+			vds:       []jwt.Validator{jwt.SubjectValidator("other")},
+			wantOuter: jwt.Header{Algorithm: "ES256", ContentType: "JWT", KeyID: "outer", Type: "JWT"},
+			wantInner: jwt.Header{Algorithm: "HS256", KeyID: "inner", Type: "JWT"},
+			err:       jwt.ErrSubValidation,
+		},
+		{
+			name:      "outer signature",
+			token:     nested,
+			outer:     jwt.NewES256(jwt.ECDSAPublicKey(es256PublicKey2)),
+			inner:     innerAlg,
+			wantOuter: jwt.Header{Algorithm: "ES256", ContentType: "JWT", KeyID: "outer", Type: "JWT"},
+			err:       jwt.ErrECDSAVerification,
+		},
+		{
+			name:      "inner signature",
+			token:     nested,
+			outer:     outerAlg,
+			inner:     jwt.NewHS256(hmacKey2),
+			wantOuter: jwt.Header{Algorithm: "ES256", ContentType: "JWT", KeyID: "outer", Type: "JWT"},
+			wantInner: jwt.Header{Algorithm: "HS256", KeyID: "inner", Type: "JWT"},
+			err:       jwt.ErrHMACVerification,
+		},
+		{
+			name:      "not nested",
+			token:     notNested,
+			outer:     outerAlg,
+			inner:     innerAlg,
+			wantOuter: jwt.Header{Algorithm: "ES256", Type: "JWT"},
+			err:       jwt.ErrNotNested,
+		},
+		{
+			name:      "not a JWT",
+			token:     notJWT,
+			outer:     outerAlg,
+			inner:     innerAlg,
+			wantOuter: jwt.Header{Algorithm: "ES256", ContentType: "JWT", Type: "JWT"},
+			err:       jwt.ErrMalformed,
+		},
+		{
+			name:      "payload not Base64URL",
+			token:     notBase64,
+			outer:     outerAlg,
+			inner:     innerAlg,
+			wantOuter: jwt.Header{Algorithm: "ES256", ContentType: "JWT", Type: "JWT"},
+			err:       jwt.ErrMalformed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pl testPayload
+			outerHd, innerHd, err := jwt.VerifyNested(tc.token, tc.outer, tc.inner, &pl, jwt.ValidatePayload(&pl.Payload, tc.vds...))
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.VerifyNested err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantOuter, outerHd; !cmp.Equal(got, want) {
+				t.Errorf("jwt.VerifyNested outer header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantInner, innerHd; !cmp.Equal(got, want) {
+				t.Errorf("jwt.VerifyNested inner header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantPayload, pl; tc.err == nil && !cmp.Equal(got, want) {
+				t.Errorf("jwt.VerifyNested payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ var _ Algorithm = none{}
 type none struct{}
 
 // None returns a dull, unsecured algorithm.
+// Verify only accepts unsecured tokens when explicitly passed this algorithm.
 func None() Algorithm { return none{} }
 
 // Name always returns "none".
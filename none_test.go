@@ -1 +1,34 @@
 package jwt_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVerifyNone(t *testing.T) {
+	testCases := []struct {
+		header string
+		alg    jwt.Algorithm
+		err    error
+	}{
+		{`{"alg":"none"}`, jwt.None(), nil},
+		{`{"alg":"none"}`, jwt.NewHS256(hmacKey1), jwt.ErrAlgNone},
+		{`{"alg":"NONE"}`, jwt.NewHS256(hmacKey1), jwt.ErrAlgNone},
+		{`{"alg":"nOnE"}`, jwt.NewRS256(jwt.RSAPublicKey(rsaPublicKey1)), jwt.ErrAlgNone},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			token := base64.RawURLEncoding.EncodeToString([]byte(tc.header)) + "." +
+				base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`)) + "."
+			var pl jwt.Payload
+			_, err := jwt.Verify([]byte(token), tc.alg, &pl, jwt.ValidatePayload(&pl, jwt.SubjectValidator("admin")))
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	"time"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// VerifyOutcome classifies the result of verifying a token.
+type VerifyOutcome int
+
+const (
+	// VerifyOK means the token was verified and all validators passed.
+	VerifyOK VerifyOutcome = iota
+	// VerifyMalformed means the token couldn't be decoded. The error wraps ErrMalformed.
+	VerifyMalformed
+	// VerifyRejected means the token was rejected before its signature was verified,
+	// for example because of its "alg" header parameter or an option.
+	VerifyRejected
+	// VerifySignatureInvalid means the token's signature is invalid. The error wraps ErrSignatureMismatch.
+	VerifySignatureInvalid
+	// VerifyClaimsInvalid means the token's signature is valid but a validator failed.
+	VerifyClaimsInvalid
+)
+
+func (o VerifyOutcome) String() string {
+	switch o {
+	case VerifyOK:
+		return "ok"
+	case VerifyMalformed:
+		return "malformed"
+	case VerifyRejected:
+		return "rejected"
+	case VerifySignatureInvalid:
+		return "signature invalid"
+	case VerifyClaimsInvalid:
+		return "claims invalid"
+	}
+	return "unknown"
+}
+
+// VerifyResult reports the outcome of a call to Verify, VerifyContext or VerifyInto.
+type VerifyResult struct {
+	// Algorithm is the "alg" header parameter, or empty if the header couldn't be decoded.
+	// Unless Outcome is VerifyOK or VerifyClaimsInvalid, it's untrusted input.
+	Algorithm string
+	// Issuer is the "iss" claim. It's only set if the signature is valid.
+	Issuer  string
+	Outcome VerifyOutcome
+	Err     error
+	Elapsed time.Duration
+}
+
+// OnVerify, if not nil, is called after each verification with its result, for example,
+// for collecting metrics of failures by reason, algorithm or issuer.
+// When nil, the default, verifying does no extra work.
+// It must be set before verifying, usually during initialization, and be safe for concurrent use.
+var OnVerify func(VerifyResult)
+
+func reportVerify(hook func(VerifyResult), rt *RawToken, verified bool, err error, start time.Time) {
+	res := VerifyResult{
+		Algorithm: rt.hd.Algorithm,
+		Outcome:   verifyOutcome(verified, err),
+		Err:       err,
+		Elapsed:   time.Since(start),
+	}
+	if verified {
+		var claims struct {
+			Issuer string `json:"iss"`
+		}
+		if JSONUnmarshal(rt.Payload(), &claims) == nil {
+			res.Issuer = claims.Issuer
+		}
+	}
+	hook(res)
+}
+
+func verifyOutcome(verified bool, err error) VerifyOutcome {
+	switch {
+	case err == nil:
+		return VerifyOK
+	case internal.ErrorIs(err, ErrMalformed):
+		return VerifyMalformed
+	case internal.ErrorIs(err, ErrSignatureMismatch):
+		return VerifySignatureInvalid
+	case verified:
+		return VerifyClaimsInvalid
+	}
+	return VerifyRejected
+}
@@ -0,0 +1,95 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOnVerify(t *testing.T) {
+	var results []jwt.VerifyResult
+	defer func(hook func(jwt.VerifyResult)) { jwt.OnVerify = hook }(jwt.OnVerify)
+	jwt.OnVerify = func(res jwt.VerifyResult) { results = append(results, res) }
+
+	hs256 := jwt.NewHS256(hmacKey1)
+	token, err := jwt.Sign(jwt.Payload{Issuer: "iss"}, hs256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name    string
+		token   []byte
+		alg     jwt.Algorithm
+		vds     []jwt.Validator
+		want    jwt.VerifyResult
+		wantErr error
+	}{
+		{
+			name:  "ok",
+			token: token,
+			alg:   hs256,
+			want:  jwt.VerifyResult{Algorithm: "HS256", Issuer: "iss", Outcome: jwt.VerifyOK},
+		},
+		{
+			name:    "malformed",
+			token:   []byte("not a token"),
+			alg:     hs256,
+			want:    jwt.VerifyResult{Outcome: jwt.VerifyMalformed},
+			wantErr: jwt.ErrMalformed,
+		},
+		{
+			name:    "rejected",
+			token:   token,
+			alg:     jwt.NewHS384(hmacKey1),
+			want:    jwt.VerifyResult{Algorithm: "HS256", Outcome: jwt.VerifyRejected},
+			wantErr: jwt.ErrAlgValidation,
+		},
+		{
+			name:    "signature invalid",
+			token:   token,
+			alg:     jwt.NewHS256(hmacKey2),
+			want:    jwt.VerifyResult{Algorithm: "HS256", Outcome: jwt.VerifySignatureInvalid},
+			wantErr: jwt.ErrSignatureMismatch,
+		},
+		{
+			name:    "claims invalid",
+			token:   token,
+			alg:     hs256,
+			vds:     []jwt.Validator{jwt.IssuerValidator("other")},
+			want:    jwt.VerifyResult{Algorithm: "HS256", Issuer: "iss", Outcome: jwt.VerifyClaimsInvalid},
+			wantErr: jwt.ErrIssValidation,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results = nil
+			var pl jwt.Payload
+			_, err := jwt.Verify(tc.token, tc.alg, &pl, jwt.ValidatePayload(&pl, tc.vds...))
+			if len(results) != 1 {
+				t.Fatalf("jwt.OnVerify called %d times, want 1", len(results))
+			}
+			got := results[0]
+			if got.Err != err {
+				t.Errorf("jwt.VerifyResult.Err = %v, want %v", got.Err, err)
+			}
+			if !internal.ErrorIs(got.Err, tc.wantErr) {
+				t.Errorf("jwt.VerifyResult.Err = %v, want %v", got.Err, tc.wantErr)
+			}
+			if got.Elapsed < 0 {
+				t.Errorf("jwt.VerifyResult.Elapsed = %v, want non-negative", got.Elapsed)
+			}
+			got.Err, got.Elapsed = nil, 0
+			if want := tc.want; got != want {
+				t.Errorf("jwt.VerifyResult mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestOnVerifyOutcomeString(t *testing.T) {
+	if want, got := "signature invalid", jwt.VerifySignatureInvalid.String(); got != want {
+		t.Errorf("jwt.VerifyOutcome.String mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
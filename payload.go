@@ -1,6 +1,44 @@
 package jwt
 
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+var (
+	// ErrClaimNotFound is the error for when a private claim is not present in a Payload.
+	ErrClaimNotFound = internal.NewError("jwt: claim not found")
+	// ErrRegisteredClaim is the error for trying to set a registered claim as a private one.
+	ErrRegisteredClaim = internal.NewError("jwt: claim is registered")
+)
+
+// registeredClaims are the claim names registered by the RFC 7519.
+var registeredClaims = map[string]struct{}{
+	"iss": {},
+	"sub": {},
+	"aud": {},
+	"exp": {},
+	"nbf": {},
+	"iat": {},
+	"jti": {},
+}
+
 // Payload is a JWT payload according to the RFC 7519.
+//
+// Private claims can be either declared in a struct that embeds Payload or,
+// when a Payload is used directly, set and read with the Set and Get methods.
+// They are kept in PrivateClaims, which is marshaled along with the
+// registered claims when signing and filled when verifying.
+// Registered claims always take precedence over private ones.
+//
+// Claims are marshaled in a fixed order, registered claims in the order of the fields below
+// and then private claims sorted by name, so signing the same Payload with a deterministic
+// algorithm, such as HMAC, always results in the same token, for example, for golden files.
 type Payload struct {
 	Issuer         string   `json:"iss,omitempty"`
 	Subject        string   `json:"sub,omitempty"`
@@ -9,4 +47,152 @@ type Payload struct {
 	NotBefore      *Time    `json:"nbf,omitempty"`
 	IssuedAt       *Time    `json:"iat,omitempty"`
 	JWTID          string   `json:"jti,omitempty"`
+
+	PrivateClaims map[string]json.RawMessage `json:"-"`
+}
+
+// Get unmarshals the private claim called name into v.
+func (pl *Payload) Get(name string, v interface{}) error {
+	raw, ok := pl.PrivateClaims[name]
+	if !ok {
+		return internal.Errorf("jwt: %q: %w", name, ErrClaimNotFound)
+	}
+	return JSONUnmarshal(raw, v)
+}
+
+// Set marshals v and stores it as the private claim called name.
+func (pl *Payload) Set(name string, v interface{}) error {
+	if _, ok := registeredClaims[name]; ok {
+		return internal.Errorf("jwt: %q: %w", name, ErrRegisteredClaim)
+	}
+	raw, err := JSONMarshal(v)
+	if err != nil {
+		return err
+	}
+	if pl.PrivateClaims == nil {
+		pl.PrivateClaims = make(map[string]json.RawMessage)
+	}
+	pl.PrivateClaims[name] = raw
+	return nil
+}
+
+// Clone returns a deep copy of pl, so it can be modified without changing pl.
+// It's meant for token exchange, where a verified Payload is narrowed down and signed again:
+//
+//	exchanged := pl.Clone()
+//	exchanged.Audience = jwt.Audience{"downstream"}
+//	exchanged.ExpirationTime = jwt.NumericDate(now.Add(5 * time.Minute))
+//	token, err := jwt.Sign(exchanged, myAlg)
+func (pl *Payload) Clone() *Payload {
+	clone := *pl
+	if pl.Audience != nil {
+		clone.Audience = append(make(Audience, 0, len(pl.Audience)), pl.Audience...)
+	}
+	clone.ExpirationTime = cloneTime(pl.ExpirationTime)
+	clone.NotBefore = cloneTime(pl.NotBefore)
+	clone.IssuedAt = cloneTime(pl.IssuedAt)
+	if pl.PrivateClaims != nil {
+		clone.PrivateClaims = make(map[string]json.RawMessage, len(pl.PrivateClaims))
+		for name, raw := range pl.PrivateClaims {
+			clone.PrivateClaims[name] = append(json.RawMessage(nil), raw...)
+		}
+	}
+	return &clone
+}
+
+func cloneTime(t *Time) *Time {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	return &clone
+}
+
+// TimeUntilExpiry returns how long until the "exp" claim, which is negative if it has passed.
+// If "exp" is not set, the token never expires and the maximum time.Duration is returned.
+func (pl *Payload) TimeUntilExpiry(now time.Time) time.Duration {
+	if pl.ExpirationTime == nil {
+		return math.MaxInt64
+	}
+	return pl.ExpirationTime.Sub(now)
+}
+
+// Expired reports whether the "exp" claim has passed at now, like ExpirationTimeValidator does.
+// Unlike the validator, it returns false when "exp" is not set.
+func (pl *Payload) Expired(now time.Time) bool {
+	return pl.ExpirationTime != nil && NumericDate(now).After(pl.ExpirationTime.Time)
+}
+
+// Valid validates the "exp", "nbf" and "iat" claims at now, as done by TimeValidator without leeway,
+// and returns the first failure. So, "exp" is required, but "nbf" and "iat" are not.
+//
+// It's a convenience for payloads decoded in trusted contexts, such as tests, and it's NOT a substitute
+// for Verify, since it doesn't check the signature, so the claims of an unverified token can't be trusted.
+func (pl *Payload) Valid(now time.Time) error {
+	return TimeValidator(now, 0)(pl)
+}
+
+// privateClaims returns the private claims of payload, if it's a Payload.
+func privateClaims(payload interface{}) map[string]json.RawMessage {
+	switch pl := payload.(type) {
+	case Payload:
+		return pl.PrivateClaims
+	case *Payload:
+		if pl != nil {
+			return pl.PrivateClaims
+		}
+	}
+	return nil
+}
+
+// appendMembers adds members to a marshaled JSON object, sorted by name.
+// Members whose names are in skip are skipped.
+func appendMembers(obj []byte, members map[string]json.RawMessage, skip map[string]struct{}) ([]byte, error) {
+	names := make([]string, 0, len(members))
+	for name := range members {
+		if _, ok := skip[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return obj, nil
+	}
+	sort.Strings(names)
+
+	obj = bytes.TrimSpace(obj)
+	end := len(obj) - 1 // drop the closing brace
+	empty := len(bytes.TrimSpace(obj[1:end])) == 0
+	buf := bytes.NewBuffer(obj[:end:end])
+	for _, name := range names {
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+		nb, err := JSONMarshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(nb)
+		buf.WriteByte(':')
+		if err = json.Compact(buf, members[name]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeMembers extracts all members of a JSON object whose names are not in skip.
+func decodeMembers(obj []byte, skip map[string]struct{}) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := JSONUnmarshal(obj, &members); err != nil {
+		return nil, err
+	}
+	for name := range skip {
+		delete(members, name)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return members, nil
 }
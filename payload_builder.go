@@ -0,0 +1,104 @@
+package jwt
+
+import (
+	"time"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// PayloadBuilder builds a Payload step by step.
+// Durations are relative to the time the builder was created.
+type PayloadBuilder struct {
+	pl  Payload
+	now time.Time
+	err error
+}
+
+// NewPayloadBuilder creates a PayloadBuilder whose durations are relative to the time returned by Now.
+func NewPayloadBuilder() *PayloadBuilder {
+	return &PayloadBuilder{now: Now()}
+}
+
+// Issuer sets the "iss" claim.
+func (b *PayloadBuilder) Issuer(iss string) *PayloadBuilder {
+	b.pl.Issuer = iss
+	return b
+}
+
+// Subject sets the "sub" claim.
+func (b *PayloadBuilder) Subject(sub string) *PayloadBuilder {
+	b.pl.Subject = sub
+	return b
+}
+
+// Audience sets the "aud" claim.
+func (b *PayloadBuilder) Audience(aud ...string) *PayloadBuilder {
+	b.pl.Audience = aud
+	return b
+}
+
+// ID sets the "jti" claim.
+func (b *PayloadBuilder) ID(jti string) *PayloadBuilder {
+	b.pl.JWTID = jti
+	return b
+}
+
+// ExpiresAt sets the "exp" claim.
+func (b *PayloadBuilder) ExpiresAt(exp time.Time) *PayloadBuilder {
+	b.pl.ExpirationTime = NumericDate(exp)
+	return b
+}
+
+// ExpiresIn sets the "exp" claim to d after the builder's creation.
+func (b *PayloadBuilder) ExpiresIn(d time.Duration) *PayloadBuilder {
+	return b.ExpiresAt(b.now.Add(d))
+}
+
+// NotBefore sets the "nbf" claim.
+func (b *PayloadBuilder) NotBefore(nbf time.Time) *PayloadBuilder {
+	b.pl.NotBefore = NumericDate(nbf)
+	return b
+}
+
+// ValidIn sets the "nbf" claim to d after the builder's creation.
+func (b *PayloadBuilder) ValidIn(d time.Duration) *PayloadBuilder {
+	return b.NotBefore(b.now.Add(d))
+}
+
+// IssuedAt sets the "iat" claim.
+func (b *PayloadBuilder) IssuedAt(iat time.Time) *PayloadBuilder {
+	b.pl.IssuedAt = NumericDate(iat)
+	return b
+}
+
+// IssuedNow sets the "iat" claim to the builder's creation time.
+func (b *PayloadBuilder) IssuedNow() *PayloadBuilder {
+	return b.IssuedAt(b.now)
+}
+
+// Claim sets a private claim. Errors are deferred until Build is called.
+func (b *PayloadBuilder) Claim(name string, v interface{}) *PayloadBuilder {
+	if err := b.pl.Set(name, v); err != nil && b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// Build returns a copy of the built Payload, so the builder can be reused, for example,
+// as a template for several tokens, without them sharing private claims or audiences.
+// It returns an error if setting a private claim has failed or if "exp" is not after "nbf" and "iat".
+func (b *PayloadBuilder) Build() (*Payload, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	pl := b.pl.Clone()
+	if exp := pl.ExpirationTime; exp != nil {
+		if nbf := pl.NotBefore; nbf != nil && !exp.After(nbf.Time) {
+			return nil, internal.Errorf("jwt: expires at %d, not valid before %d: %w", exp.Unix(), nbf.Unix(), ErrExpValidation)
+		}
+		if iat := pl.IssuedAt; iat != nil && !exp.After(iat.Time) {
+			return nil, internal.Errorf("jwt: expires at %d, issued at %d: %w", exp.Unix(), iat.Unix(), ErrExpValidation)
+		}
+	}
+	return pl, nil
+}
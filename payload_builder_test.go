@@ -0,0 +1,95 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPayloadBuilder(t *testing.T) {
+	now := time.Now()
+	pl, err := jwt.NewPayloadBuilder().
+		Issuer("gbrlsnchs").
+		Subject("someone").
+		Audience("https://golang.org", "https://jwt.io").
+		ID("foobar").
+		IssuedNow().
+		ValidIn(30*time.Minute).
+		ExpiresIn(time.Hour).
+		Claim("tenant", "acme").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &jwt.Payload{
+		Issuer:         "gbrlsnchs",
+		Subject:        "someone",
+		Audience:       jwt.Audience{"https://golang.org", "https://jwt.io"},
+		ExpirationTime: jwt.NumericDate(pl.IssuedAt.Add(time.Hour)),
+		NotBefore:      jwt.NumericDate(pl.IssuedAt.Add(30 * time.Minute)),
+		IssuedAt:       pl.IssuedAt,
+		JWTID:          "foobar",
+	}
+	if err = want.Set("tenant", "acme"); err != nil {
+		t.Fatal(err)
+	}
+	if got := pl; !cmp.Equal(got, want) {
+		t.Errorf("jwt.PayloadBuilder.Build mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if d := pl.IssuedAt.Sub(now); d < -time.Second || d > time.Second {
+		t.Errorf("jwt.PayloadBuilder.IssuedNow is off by %v", d)
+	}
+}
+
+func TestPayloadBuilderReuse(t *testing.T) {
+	b := jwt.NewPayloadBuilder().Issuer("gbrlsnchs").Audience("foo").Claim("tenant", "acme")
+	first, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Audience[0] = "bar"
+	if err = first.Set("tenant", "other"); err != nil {
+		t.Fatal(err)
+	}
+	second, err := b.Subject("someone").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &jwt.Payload{Issuer: "gbrlsnchs", Subject: "someone", Audience: jwt.Audience{"foo"}}
+	if err = want.Set("tenant", "acme"); err != nil {
+		t.Fatal(err)
+	}
+	if got := second; !cmp.Equal(got, want) {
+		t.Errorf("jwt.PayloadBuilder.Build mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := "", first.Subject; got != want {
+		t.Errorf("jwt.PayloadBuilder.Build changed a previous payload (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestPayloadBuilderErrors(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		b   *jwt.PayloadBuilder
+		err error
+	}{
+		{jwt.NewPayloadBuilder().ExpiresIn(time.Hour).ValidIn(30 * time.Minute).IssuedNow(), nil},
+		{jwt.NewPayloadBuilder().ExpiresIn(time.Hour).ValidIn(time.Hour), jwt.ErrExpValidation},
+		{jwt.NewPayloadBuilder().ExpiresIn(time.Hour).ValidIn(2 * time.Hour), jwt.ErrExpValidation},
+		{jwt.NewPayloadBuilder().ExpiresIn(-time.Hour).IssuedNow(), jwt.ErrExpValidation},
+		{jwt.NewPayloadBuilder().ExpiresAt(now).IssuedAt(now), jwt.ErrExpValidation},
+		{jwt.NewPayloadBuilder().ValidIn(time.Hour), nil},
+		{jwt.NewPayloadBuilder().Claim("exp", 0), jwt.ErrRegisteredClaim},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			_, err := tc.b.Build()
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.PayloadBuilder.Build err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
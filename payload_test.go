@@ -0,0 +1,233 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPayloadPrivateClaims(t *testing.T) {
+	var pl jwt.Payload
+	pl.Issuer = "gbrlsnchs"
+	if err := pl.Set("roles", []string{"admin", "user"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pl.Set("tenant_id", 1337); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := jwt.ErrRegisteredClaim, pl.Set("iss", "evil"); !internal.ErrorIs(got, want) {
+		t.Fatalf("jwt.Payload.Set err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	pl.PrivateClaims["sub"] = json.RawMessage(`"evil"`) // must be ignored when signing
+
+	hs256 := jwt.NewHS256([]byte("secret"))
+	token, err := jwt.Sign(&pl, hs256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jwt.Payload
+	if _, err = jwt.Verify(token, hs256, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "gbrlsnchs", got.Issuer; got != want {
+		t.Errorf("jwt.Payload.Issuer mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := "", got.Subject; got != want {
+		t.Errorf("jwt.Payload.Subject mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	var roles []string
+	if err = got.Get("roles", &roles); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := []string{"admin", "user"}, roles; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Payload.Get mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	var tenantID int
+	if err = got.Get("tenant_id", &tenantID); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1337, tenantID; got != want {
+		t.Errorf("jwt.Payload.Get mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := jwt.ErrClaimNotFound, got.Get("foo", new(string)); !internal.ErrorIs(got, want) {
+		t.Errorf("jwt.Payload.Get err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestPayloadPrivateClaimsOnly(t *testing.T) {
+	pl := jwt.Payload{PrivateClaims: map[string]json.RawMessage{
+		"b": json.RawMessage(` { "x" : 1 } `),
+		"a": json.RawMessage(`true`),
+	}}
+	hs256 := jwt.NewHS256([]byte("secret"))
+	token, err := jwt.Sign(pl, hs256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]json.RawMessage
+	if _, err = jwt.Verify(token, hs256, &raw); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]json.RawMessage{
+		"a": json.RawMessage(`true`),
+		"b": json.RawMessage(`{"x":1}`),
+	}
+	if !cmp.Equal(raw, want) {
+		t.Errorf("private claims mismatch (-want +got):\n%s", cmp.Diff(want, raw))
+	}
+}
+
+func TestPayloadInvalidPrivateClaim(t *testing.T) {
+	pl := jwt.Payload{PrivateClaims: map[string]json.RawMessage{
+		"foo": json.RawMessage(`1,"exp":0`),
+	}}
+	if _, err := jwt.Sign(pl, jwt.NewHS256([]byte("secret"))); err == nil {
+		t.Fatal("jwt.Sign didn't fail with invalid private claim")
+	}
+}
+
+func TestPayloadExpiry(t *testing.T) {
+	now := time.Unix(1000, 0)
+	testCases := []struct {
+		name        string
+		exp         *jwt.Time
+		wantUntil   time.Duration
+		wantExpired bool
+	}{
+		{"future", jwt.NumericDate(now.Add(time.Hour)), time.Hour, false},
+		{"now", jwt.NumericDate(now), 0, false},
+		{"past", jwt.NumericDate(now.Add(-time.Minute)), -time.Minute, true},
+		{"epoch", jwt.NumericDate(time.Unix(0, 0)), -1000 * time.Second, true},
+		{"missing", nil, math.MaxInt64, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pl := jwt.Payload{ExpirationTime: tc.exp}
+			if want, got := tc.wantUntil, pl.TimeUntilExpiry(now); got != want {
+				t.Errorf("jwt.Payload.TimeUntilExpiry mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantExpired, pl.Expired(now); got != want {
+				t.Errorf("jwt.Payload.Expired mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestPayloadValid(t *testing.T) {
+	now := time.Unix(1000, 0)
+	testCases := []struct {
+		name string
+		pl   jwt.Payload
+		err  error
+	}{
+		{"valid", jwt.Payload{ExpirationTime: jwt.NumericDate(now.Add(time.Hour)), NotBefore: jwt.NumericDate(now), IssuedAt: jwt.NumericDate(now)}, nil},
+		{"only exp", jwt.Payload{ExpirationTime: jwt.NumericDate(now)}, nil},
+		{"missing exp", jwt.Payload{}, jwt.ErrExpValidation},
+		{"expired", jwt.Payload{ExpirationTime: jwt.NumericDate(now.Add(-time.Second))}, jwt.ErrExpValidation},
+		{"not yet valid", jwt.Payload{ExpirationTime: jwt.NumericDate(now.Add(time.Hour)), NotBefore: jwt.NumericDate(now.Add(time.Second))}, jwt.ErrNbfValidation},
+		{"issued in the future", jwt.Payload{ExpirationTime: jwt.NumericDate(now.Add(time.Hour)), IssuedAt: jwt.NumericDate(now.Add(time.Second))}, jwt.ErrIatValidation},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if want, got := tc.err, tc.pl.Valid(now); !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Payload.Valid err = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPayloadClone(t *testing.T) {
+	now := time.Unix(1000, 0)
+	src := jwt.Payload{
+		Issuer:         "iss",
+		Audience:       make(jwt.Audience, 1, 4), // spare capacity would be shared by a shallow copy
+		ExpirationTime: jwt.NumericDate(now.Add(time.Hour)),
+		IssuedAt:       jwt.NumericDate(now),
+	}
+	src.Audience[0] = "aud"
+	if err := src.Set("scope", "read"); err != nil {
+		t.Fatal(err)
+	}
+	want := src.Clone()
+	if diff := cmp.Diff(&src, want); diff != "" {
+		t.Fatalf("jwt.Payload.Clone mismatch (-want +got):\n%s", diff)
+	}
+
+	clone := src.Clone()
+	clone.Audience = append(clone.Audience, "other")
+	clone.Audience[0] = "changed"
+	clone.ExpirationTime.Time = now
+	clone.PrivateClaims["scope"][1] = 'w'
+	if err := clone.Set("extra", true); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, &src); diff != "" {
+		t.Errorf("source Payload mismatch after modifying clone (-want +got):\n%s", diff)
+	}
+	if got := src.Audience[:cap(src.Audience)][1]; got != "" {
+		t.Errorf("source Audience backing array is shared, got %q", got)
+	}
+}
+
+func TestPayloadMarshalOmitEmpty(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	testCases := []struct {
+		name string
+		pl   jwt.Payload
+		want string
+	}{
+		{"empty", jwt.Payload{}, `{}`},
+		{"empty audience", jwt.Payload{Audience: jwt.Audience{}}, `{}`},
+		{"empty private claims", jwt.Payload{PrivateClaims: map[string]json.RawMessage{}}, `{}`},
+		{"issuer", jwt.Payload{Issuer: "iss"}, `{"iss":"iss"}`},
+		{"epoch", jwt.Payload{IssuedAt: jwt.NumericDate(time.Unix(0, 0))}, `{"iat":0}`}, // explicitly set
+		{
+			"all",
+			jwt.Payload{
+				Issuer:         "iss",
+				Subject:        "sub",
+				Audience:       jwt.Audience{"aud"},
+				ExpirationTime: jwt.NumericDate(now.Add(time.Hour)),
+				NotBefore:      jwt.NumericDate(now),
+				IssuedAt:       jwt.NumericDate(now),
+				JWTID:          "jti",
+			},
+			`{"iss":"iss","sub":"sub","aud":"aud","exp":1500003600,"nbf":1500000000,"iat":1500000000,"jti":"jti"}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := jwt.Sign(tc.pl, jwt.NewHS256(hmacKey1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, err := jwt.Decode(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, got := tc.want, string(raw.Payload()); got != want {
+				t.Errorf("jwt.Sign payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			var pl jwt.Payload
+			if _, err = jwt.Verify(token, jwt.NewHS256(hmacKey1), &pl); err != nil {
+				t.Fatal(err)
+			}
+			if want, got := tc.want, mustMarshal(t, pl); got != want {
+				t.Errorf("round trip mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,141 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+var (
+	// ErrPEMInvalid is the error for when PEM data has no valid key.
+	ErrPEMInvalid = internal.NewError("jwt: invalid PEM data")
+	// ErrPEMEncrypted is the error for when a PEM block is encrypted.
+	ErrPEMEncrypted = internal.NewError("jwt: PEM block is encrypted")
+	// ErrPEMKeyType is the error for when a PEM block holds a key of an unexpected type.
+	ErrPEMKeyType = internal.NewError("jwt: PEM block has an unexpected key type")
+)
+
+// ParseRSAPrivateKeyFromPEM parses a PEM encoded PKCS #1 or PKCS #8 RSA private key.
+func ParseRSAPrivateKeyFromPEM(data []byte) (*rsa.PrivateKey, error) {
+	key, err := parsePrivateKeyPEM(data, "RSA PRIVATE KEY", func(der []byte) (interface{}, error) {
+		return x509.ParsePKCS1PrivateKey(der)
+	})
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, internal.Errorf("jwt: got %T, want RSA: %w", key, ErrPEMKeyType)
+	}
+	return priv, nil
+}
+
+// ParseRSAPublicKeyFromPEM parses a PEM encoded PKIX or PKCS #1 RSA public key,
+// or the public key from a PEM encoded certificate.
+func ParseRSAPublicKeyFromPEM(data []byte) (*rsa.PublicKey, error) {
+	key, err := parsePublicKeyPEM(data, "RSA PUBLIC KEY", func(der []byte) (interface{}, error) {
+		return x509.ParsePKCS1PublicKey(der)
+	})
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, internal.Errorf("jwt: got %T, want RSA: %w", key, ErrPEMKeyType)
+	}
+	return pub, nil
+}
+
+// ParseECPrivateKeyFromPEM parses a PEM encoded SEC 1 or PKCS #8 elliptic curve private key.
+func ParseECPrivateKeyFromPEM(data []byte) (*ecdsa.PrivateKey, error) {
+	key, err := parsePrivateKeyPEM(data, "EC PRIVATE KEY", func(der []byte) (interface{}, error) {
+		return x509.ParseECPrivateKey(der)
+	})
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, internal.Errorf("jwt: got %T, want ECDSA: %w", key, ErrPEMKeyType)
+	}
+	return priv, nil
+}
+
+// ParseECPublicKeyFromPEM parses a PEM encoded PKIX elliptic curve public key,
+// or the public key from a PEM encoded certificate.
+func ParseECPublicKeyFromPEM(data []byte) (*ecdsa.PublicKey, error) {
+	key, err := parsePublicKeyPEM(data, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, internal.Errorf("jwt: got %T, want ECDSA: %w", key, ErrPEMKeyType)
+	}
+	return pub, nil
+}
+
+// decodePEM decodes the first PEM block in data, rejecting encrypted ones.
+func decodePEM(data []byte) (*pem.Block, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, internal.Errorf("jwt: no PEM block found: %w", ErrPEMInvalid)
+	}
+	if block.Type == "ENCRYPTED PRIVATE KEY" || strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED") {
+		return nil, ErrPEMEncrypted
+	}
+	return block, nil
+}
+
+// parsePrivateKeyPEM parses a PKCS #8 private key or, if the PEM block type is typ, a key using parse.
+func parsePrivateKeyPEM(data []byte, typ string, parse func([]byte) (interface{}, error)) (interface{}, error) {
+	block, err := decodePEM(data)
+	if err != nil {
+		return nil, err
+	}
+	var key interface{}
+	switch block.Type {
+	case typ:
+		key, err = parse(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, internal.Errorf("jwt: %q block: %w", block.Type, ErrPEMKeyType)
+	}
+	if err != nil {
+		return nil, internal.Errorf("jwt: %v: %w", err, ErrPEMInvalid)
+	}
+	return key, nil
+}
+
+// parsePublicKeyPEM parses a PKIX public key, a certificate or,
+// if the PEM block type is typ, a key using parse.
+func parsePublicKeyPEM(data []byte, typ string, parse func([]byte) (interface{}, error)) (interface{}, error) {
+	block, err := decodePEM(data)
+	if err != nil {
+		return nil, err
+	}
+	var key interface{}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		if typ == "" || block.Type != typ {
+			return nil, internal.Errorf("jwt: %q block: %w", block.Type, ErrPEMKeyType)
+		}
+		key, err = parse(block.Bytes)
+	}
+	if err != nil {
+		return nil, internal.Errorf("jwt: %v: %w", err, ErrPEMInvalid)
+	}
+	return key, nil
+}
@@ -0,0 +1,109 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePEM(t *testing.T) {
+	var (
+		rsaPKCS1    = encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaPrivateKey1))
+		rsaPKCS8    = encodePEM("PRIVATE KEY", mustDER(t)(x509.MarshalPKCS8PrivateKey(rsaPrivateKey1)))
+		rsaPubPKCS1 = encodePEM("RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(rsaPublicKey1))
+		rsaPubPKIX  = encodePEM("PUBLIC KEY", mustDER(t)(x509.MarshalPKIXPublicKey(rsaPublicKey1)))
+		ecSEC1      = encodePEM("EC PRIVATE KEY", mustDER(t)(x509.MarshalECPrivateKey(es256PrivateKey1)))
+		ecPKCS8     = encodePEM("PRIVATE KEY", mustDER(t)(x509.MarshalPKCS8PrivateKey(es256PrivateKey1)))
+		ecPubPKIX   = encodePEM("PUBLIC KEY", mustDER(t)(x509.MarshalPKIXPublicKey(es256PublicKey1)))
+		encrypted   = pem.EncodeToMemory(&pem.Block{
+			Type:    "RSA PRIVATE KEY",
+			Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-256-CBC,00"},
+			Bytes:   []byte("encrypted"),
+		})
+		encryptedPKCS8 = encodePEM("ENCRYPTED PRIVATE KEY", []byte("encrypted"))
+		garbage        = encodePEM("PRIVATE KEY", []byte("garbage"))
+	)
+
+	testCases := []struct {
+		name  string
+		parse func([]byte) (interface{}, error)
+		data  []byte
+		want  interface{}
+		err   error
+	}{
+		{"RSA PKCS #1", parseRSAPrivateKey, rsaPKCS1, rsaPrivateKey1, nil},
+		{"RSA PKCS #8", parseRSAPrivateKey, rsaPKCS8, rsaPrivateKey1, nil},
+		{"RSA encrypted", parseRSAPrivateKey, encrypted, nil, jwt.ErrPEMEncrypted},
+		{"RSA encrypted PKCS #8", parseRSAPrivateKey, encryptedPKCS8, nil, jwt.ErrPEMEncrypted},
+		{"RSA from EC", parseRSAPrivateKey, ecPKCS8, nil, jwt.ErrPEMKeyType},
+		{"RSA from SEC 1", parseRSAPrivateKey, ecSEC1, nil, jwt.ErrPEMKeyType},
+		{"RSA garbage", parseRSAPrivateKey, garbage, nil, jwt.ErrPEMInvalid},
+		{"RSA empty", parseRSAPrivateKey, nil, nil, jwt.ErrPEMInvalid},
+		{"RSA public PKCS #1", parseRSAPublicKey, rsaPubPKCS1, rsaPublicKey1, nil},
+		{"RSA public PKIX", parseRSAPublicKey, rsaPubPKIX, rsaPublicKey1, nil},
+		{"RSA public from EC", parseRSAPublicKey, ecPubPKIX, nil, jwt.ErrPEMKeyType},
+		{"RSA public from private", parseRSAPublicKey, rsaPKCS1, nil, jwt.ErrPEMKeyType},
+		{"EC SEC 1", parseECPrivateKey, ecSEC1, es256PrivateKey1, nil},
+		{"EC PKCS #8", parseECPrivateKey, ecPKCS8, es256PrivateKey1, nil},
+		{"EC from RSA", parseECPrivateKey, rsaPKCS8, nil, jwt.ErrPEMKeyType},
+		{"EC public PKIX", parseECPublicKey, ecPubPKIX, es256PublicKey1, nil},
+		{"EC public from RSA", parseECPublicKey, rsaPubPKIX, nil, jwt.ErrPEMKeyType},
+		{"EC public from PKCS #1", parseECPublicKey, rsaPubPKCS1, nil, jwt.ErrPEMKeyType},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := tc.parse(tc.data)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.Parse*FromPEM err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if err != nil {
+				return
+			}
+			if !keysEqual(tc.want, key) {
+				t.Errorf("jwt.Parse*FromPEM key mismatch")
+			}
+		})
+	}
+}
+
+func mustDER(t *testing.T) func([]byte, error) []byte {
+	return func(der []byte, err error) []byte {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der
+	}
+}
+
+func encodePEM(typ string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
+
+func keysEqual(want, got interface{}) bool {
+	switch want := want.(type) {
+	case *rsa.PrivateKey:
+		got, ok := got.(*rsa.PrivateKey)
+		return ok && got.D.Cmp(want.D) == 0 && got.N.Cmp(want.N) == 0
+	case *rsa.PublicKey:
+		got, ok := got.(*rsa.PublicKey)
+		return ok && got.N.Cmp(want.N) == 0 && got.E == want.E
+	case *ecdsa.PrivateKey:
+		got, ok := got.(*ecdsa.PrivateKey)
+		return ok && got.D.Cmp(want.D) == 0 && got.Curve == want.Curve
+	case *ecdsa.PublicKey:
+		got, ok := got.(*ecdsa.PublicKey)
+		return ok && got.X.Cmp(want.X) == 0 && got.Y.Cmp(want.Y) == 0 && got.Curve == want.Curve
+	}
+	return false
+}
+
+func parseRSAPrivateKey(data []byte) (interface{}, error) { return jwt.ParseRSAPrivateKeyFromPEM(data) }
+func parseRSAPublicKey(data []byte) (interface{}, error)  { return jwt.ParseRSAPublicKeyFromPEM(data) }
+func parseECPrivateKey(data []byte) (interface{}, error)  { return jwt.ParseECPrivateKeyFromPEM(data) }
+func parseECPublicKey(data []byte) (interface{}, error)   { return jwt.ParseECPublicKeyFromPEM(data) }
@@ -1,24 +1,73 @@
 package jwt
 
 import (
-	"encoding/json"
+	"bytes"
+	"encoding/base64"
+	"sort"
 
 	"github.com/gbrlsnchs/jwt/v3/internal"
 )
 
 // ErrMalformed indicates a token doesn't have a valid format, as per the RFC 7519.
+// Errors for parts that can't be decoded wrap both ErrMalformed and the decoding error.
 var ErrMalformed = internal.NewError("jwt: malformed token")
 
+// ErrTokenTooLarge indicates a token or its header exceeds its maximum size.
+// It wraps ErrMalformed.
+var ErrTokenTooLarge = internal.WrapError("jwt: token is too large", ErrMalformed)
+
+const (
+	// DefaultMaxTokenSize is the maximum byte size of a token accepted when verifying or decoding,
+	// which bounds the work done for untrusted input, unless overridden by the MaxTokenSize option.
+	DefaultMaxTokenSize = 8 << 10
+	// DefaultMaxHeaderSize is the maximum byte size of a token's encoded header,
+	// unless overridden by the MaxHeaderSize option.
+	DefaultMaxHeaderSize = 4 << 10
+)
+
+type malformedError struct {
+	err error
+}
+
+func malformed(err error) error {
+	return &malformedError{err}
+}
+
+func (e *malformedError) Error() string { return ErrMalformed.Error() + ": " + e.err.Error() }
+
+func (e *malformedError) Is(target error) bool { return target == ErrMalformed }
+
+func (e *malformedError) Unwrap() error { return e.err }
+
 // RawToken is a representation of a parsed JWT string.
+//
+// Its exported methods give access to the token's parts, for example for logging or caching.
+// For RawTokens received by a VerifyOption, whose parts haven't been decoded yet,
+// Header, Payload and Signature return nil if their parts are not valid Base64URL.
 type RawToken struct {
 	token      []byte
 	sep1, sep2 int
 
-	hd  Header
-	alg Algorithm
+	hd       Header
+	hdParams []string // names of the header parameters, as present in the token
+	alg      Algorithm
+
+	pl            *Payload
+	vds           []Validator
+	rawVds        []RawValidator
+	reportIndex   bool
+	signatureOnly bool
 
-	pl  *Payload
-	vds []Validator
+	critParams []string
+	strictJSON bool
+	hdChecks   []VerifyOption
+
+	detached   []byte
+	isDetached bool
+
+	maxTokenSize        int
+	maxHeaderSize       int
+	maxDecompressedSize int64
 }
 
 func (rt *RawToken) header() []byte        { return rt.token[:rt.sep1] }
@@ -26,6 +75,118 @@ func (rt *RawToken) headerPayload() []byte { return rt.token[:rt.sep2] }
 func (rt *RawToken) payload() []byte       { return rt.token[rt.sep1+1 : rt.sep2] }
 func (rt *RawToken) sig() []byte           { return rt.token[rt.sep2+1:] }
 
+// CountSegments returns the number of dot-separated segments in token, without decoding any of them,
+// so a JWS, which has 3 segments, can be told apart from a JWE, which has 5.
+// Any other number of segments, as well as an empty header, results in ErrMalformed.
+func CountSegments(token []byte) (int, error) {
+	if err := checkSize(token, DefaultMaxTokenSize, DefaultMaxHeaderSize); err != nil {
+		return 0, err
+	}
+	if len(token) == 0 || token[0] == '.' {
+		return 0, ErrMalformed
+	}
+	if n := bytes.Count(token, []byte{'.'}) + 1; n == 3 || n == 5 {
+		return n, nil
+	}
+	return 0, ErrMalformed
+}
+
+// Decode splits token into its parts and decodes them WITHOUT verifying its signature.
+// Like ParseUnverified, it must not be used for trusting a token.
+func Decode(token []byte) (*RawToken, error) {
+	var rt RawToken
+	if err := checkSize(token, DefaultMaxTokenSize, DefaultMaxHeaderSize); err != nil {
+		return nil, err
+	}
+	if err := rt.parse(token); err != nil {
+		return nil, err
+	}
+	for _, part := range [][]byte{rt.payload(), rt.sig()} {
+		if _, err := internal.DecodeToBytes(part); err != nil {
+			return nil, malformed(err)
+		}
+	}
+	return &rt, nil
+}
+
+// Header returns the token's decoded JOSE header, as JSON.
+func (rt *RawToken) Header() []byte {
+	hb, _ := internal.DecodeToBytes(rt.header())
+	return hb
+}
+
+// Payload returns the token's decoded payload, usually JSON.
+func (rt *RawToken) Payload() []byte {
+	pb, _ := internal.DecodeToBytes(rt.payload())
+	return pb
+}
+
+// Signature returns the token's decoded signature.
+func (rt *RawToken) Signature() []byte {
+	sig, _ := internal.DecodeToBytes(rt.sig())
+	return sig
+}
+
+// SigningInput returns the data the token's signature is computed from,
+// that is, its encoded header and payload separated by a dot.
+func (rt *RawToken) SigningInput() []byte {
+	return rt.signingInput()
+}
+
+// signingInput returns the data the token's signature is computed from.
+// Unless the payload is detached, it's a slice of the token itself, which is never re-encoded,
+// so signatures are verified over the exact bytes that were signed.
+func (rt *RawToken) signingInput() []byte {
+	if !rt.isDetached {
+		return rt.headerPayload()
+	}
+	payload := rt.detached
+	if rt.hd.Base64 == nil || *rt.hd.Base64 {
+		payload = make([]byte, base64.RawURLEncoding.EncodedLen(len(rt.detached)))
+		base64.RawURLEncoding.Encode(payload, rt.detached)
+	}
+	input := make([]byte, 0, rt.sep1+1+len(payload))
+	input = append(input, rt.header()...)
+	input = append(input, '.')
+	return append(input, payload...)
+}
+
+// parse splits token into its parts and decodes its header.
+func (rt *RawToken) parse(token []byte) error {
+	if err := rt.split(token); err != nil {
+		return err
+	}
+	return rt.decodeHeader()
+}
+
+// split splits token into its parts, without decoding any of them.
+func (rt *RawToken) split(token []byte) error {
+	sep1 := bytes.IndexByte(token, '.')
+	if sep1 < 0 {
+		return ErrMalformed
+	}
+
+	cbytes := token[sep1+1:]
+	sep2 := bytes.IndexByte(cbytes, '.')
+	if sep2 < 0 {
+		return ErrMalformed
+	}
+	rt.setToken(token, sep1, sep2)
+	return nil
+}
+
+// checkSize checks that token and its encoded header are within their maximum sizes.
+// Zero means no limit.
+func checkSize(token []byte, maxToken, maxHeader int) error {
+	if maxToken > 0 && len(token) > maxToken {
+		return internal.Errorf("jwt: token has more than %d bytes: %w", maxToken, ErrTokenTooLarge)
+	}
+	if sep := bytes.IndexByte(token, '.'); maxHeader > 0 && sep > maxHeader {
+		return internal.Errorf("jwt: header has more than %d bytes: %w", maxHeader, ErrTokenTooLarge)
+	}
+	return nil
+}
+
 func (rt *RawToken) setToken(token []byte, sep1, sep2 int) {
 	rt.sep1 = sep1
 	rt.sep2 = sep1 + 1 + sep2
@@ -35,16 +196,42 @@ func (rt *RawToken) setToken(token []byte, sep1, sep2 int) {
 func (rt *RawToken) decode(payload interface{}) (err error) {
 	pb, err := internal.DecodeToBytes(rt.payload())
 	if err != nil {
-		return err
+		return malformed(err)
 	}
+	return rt.unmarshal(pb, payload)
+}
+
+// unmarshal unmarshals a decoded payload and runs the validators against it.
+func (rt *RawToken) unmarshal(pb []byte, payload interface{}) (err error) {
 	if !isJSONObject(pb) {
-		return ErrNotJSONObject
+		return malformed(ErrNotJSONObject)
 	}
-	if err = json.Unmarshal(pb, payload); err != nil {
-		return err
+	if rt.strictJSON {
+		if err = checkDuplicateKeys(pb); err != nil {
+			return err
+		}
+	}
+	if err = JSONUnmarshal(pb, payload); err != nil {
+		return malformed(err)
 	}
-	for _, vd := range rt.vds {
+	if pl, ok := payload.(*Payload); ok {
+		if pl.PrivateClaims, err = decodeMembers(pb, registeredClaims); err != nil {
+			return malformed(err)
+		}
+	}
+	if rt.signatureOnly {
+		return nil
+	}
+	for i, vd := range rt.vds {
 		if err = vd(rt.pl); err != nil {
+			if rt.reportIndex {
+				return &ValidatorError{Index: i, Err: err}
+			}
+			return err
+		}
+	}
+	for _, vd := range rt.rawVds {
+		if err = vd(pb); err != nil {
 			return err
 		}
 	}
@@ -52,8 +239,46 @@ func (rt *RawToken) decode(payload interface{}) (err error) {
 }
 
 func (rt *RawToken) decodeHeader() error {
-	if err := internal.Decode(rt.header(), &rt.hd); err != nil {
-		return err
+	hb, err := internal.DecodeToBytes(rt.header())
+	if err != nil {
+		return malformed(err)
+	}
+	return rt.unmarshalHeader(hb)
+}
+
+func (rt *RawToken) unmarshalHeader(hb []byte) (err error) {
+	if rt.strictJSON {
+		if err = checkDuplicateKeys(hb); err != nil {
+			return err
+		}
+	}
+	if err = JSONUnmarshal(hb, &rt.hd); err != nil {
+		return malformed(err)
+	}
+	members, err := decodeMembers(hb, nil)
+	if err != nil {
+		return malformed(err)
+	}
+	rt.hdParams = make([]string, 0, len(members))
+	for name := range members {
+		rt.hdParams = append(rt.hdParams, name)
+		if _, ok := headerParams[name]; ok {
+			delete(members, name)
+		}
+	}
+	sort.Strings(rt.hdParams)
+	if len(members) > 0 {
+		rt.hd.Extra = members
+	}
+	return nil
+}
+
+// checkHeader runs the checks deferred by options until the header is decoded.
+func (rt *RawToken) checkHeader() error {
+	for _, check := range rt.hdChecks {
+		if err := check(rt); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -0,0 +1,84 @@
+package jwt_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecode(t *testing.T) {
+	alg := jwt.NewHS256(hmacKey1)
+	token, err := jwt.Sign(jwt.Payload{Subject: "someone"}, alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt, err := jwt.Decode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := `{"alg":"HS256","typ":"JWT"}`, string(rt.Header()); got != want {
+		t.Errorf("jwt.RawToken.Header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := `{"sub":"someone"}`, string(rt.Payload()); got != want {
+		t.Errorf("jwt.RawToken.Payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := token[:bytes.LastIndexByte(token, '.')], rt.SigningInput(); !bytes.Equal(got, want) {
+		t.Errorf("jwt.RawToken.SigningInput mismatch (-want +got):\n%s", cmp.Diff(string(want), string(got)))
+	}
+	sig, err := alg.Sign(rt.SigningInput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := sig, rt.Signature(); !bytes.Equal(got, want) {
+		t.Errorf("jwt.RawToken.Signature mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	for _, token := range []string{"foo", "e30.!!!.", "e30.e30.!!!"} {
+		t.Run(token, func(t *testing.T) {
+			_, err := jwt.Decode([]byte(token))
+			if want, got := jwt.ErrMalformed, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Decode err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestCountSegments(t *testing.T) {
+	jws, err := jwt.Sign(tp, jwt.NewHS256(hmacKey1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwe, err := jwt.EncryptClaims(tp, rsaPublicKey1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		token []byte
+		want  int
+		err   error
+	}{
+		{jws, 3, nil},
+		{jwe, 5, nil},
+		{[]byte("e30.."), 3, nil},
+		{[]byte(""), 0, jwt.ErrMalformed},
+		{[]byte("e30"), 0, jwt.ErrMalformed},
+		{[]byte("e30.e30"), 0, jwt.ErrMalformed},
+		{[]byte("..."), 0, jwt.ErrMalformed},
+		{[]byte("e30.e30.e30.e30"), 0, jwt.ErrMalformed},
+		{[]byte(".e30.e30"), 0, jwt.ErrMalformed},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.token), func(t *testing.T) {
+			n, err := jwt.CountSegments(tc.token)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.CountSegments err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.want, n; got != want {
+				t.Errorf("jwt.CountSegments mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
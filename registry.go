@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// ErrUnsupportedKey is the error for creating an algorithm with a key of the wrong type.
+var ErrUnsupportedKey = internal.NewError("jwt: key type is not supported by algorithm")
+
+// AlgorithmFactory creates an algorithm from a key. Private keys create algorithms that
+// can both sign and verify, while public keys create algorithms that can only verify.
+//
+// Unlike the constructors of algorithms, factories return errors instead of panicking,
+// since keys are usually loaded from configuration.
+type AlgorithmFactory func(key interface{}) (Algorithm, error)
+
+var algorithmFactories = map[string]AlgorithmFactory{
+	"HS256":  hmacFactory(NewHS256),
+	"HS384":  hmacFactory(NewHS384),
+	"HS512":  hmacFactory(NewHS512),
+	"RS256":  rsaFactory(NewRS256),
+	"RS384":  rsaFactory(NewRS384),
+	"RS512":  rsaFactory(NewRS512),
+	"PS256":  rsaFactory(NewPS256),
+	"PS384":  rsaFactory(NewPS384),
+	"PS512":  rsaFactory(NewPS512),
+	"ES256":  ecdsaFactory(NewES256),
+	"ES384":  ecdsaFactory(NewES384),
+	"ES512":  ecdsaFactory(NewES512),
+	"ES256K": ecdsaFactory(NewES256K),
+	"EdDSA":  ed25519Factory,
+}
+
+// AlgorithmByName returns the factory for the algorithm called name, as set in the "alg"
+// header parameter, so algorithms can be created from configuration:
+//
+//	newAlg, ok := jwt.AlgorithmByName(cfg.Algorithm)
+//	if !ok {
+//		return fmt.Errorf("unsupported algorithm %q", cfg.Algorithm)
+//	}
+//	alg, err := newAlg(key)
+//
+// Keys are []byte or string for HMAC-SHA, *rsa.PrivateKey or *rsa.PublicKey for RSA-SHA,
+// *ecdsa.PrivateKey or *ecdsa.PublicKey for ECDSA-SHA and ed25519.PrivateKey or ed25519.PublicKey for EdDSA.
+// The "none" algorithm is not registered.
+func AlgorithmByName(name string) (AlgorithmFactory, bool) {
+	f, ok := algorithmFactories[name]
+	return f, ok
+}
+
+func hmacFactory(newAlg func([]byte) *HMACSHA) AlgorithmFactory {
+	return func(key interface{}) (Algorithm, error) {
+		var secret []byte
+		switch key := key.(type) {
+		case []byte:
+			secret = key
+		case string:
+			secret = []byte(key)
+		default:
+			return nil, internal.Errorf("jwt: %T: %w", key, ErrUnsupportedKey)
+		}
+		return construct(func() Algorithm { return newAlg(secret) })
+	}
+}
+
+func rsaFactory(newAlg func(...func(*RSASHA)) *RSASHA) AlgorithmFactory {
+	return func(key interface{}) (Algorithm, error) {
+		var opt func(*RSASHA)
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			opt = RSAPrivateKey(key)
+		case *rsa.PublicKey:
+			opt = RSAPublicKey(key)
+		default:
+			return nil, internal.Errorf("jwt: %T: %w", key, ErrUnsupportedKey)
+		}
+		return construct(func() Algorithm { return newAlg(opt) })
+	}
+}
+
+func ecdsaFactory(newAlg func(...func(*ECDSASHA)) *ECDSASHA) AlgorithmFactory {
+	return func(key interface{}) (Algorithm, error) {
+		var opt func(*ECDSASHA)
+		switch key := key.(type) {
+		case *ecdsa.PrivateKey:
+			opt = ECDSAPrivateKey(key)
+		case *ecdsa.PublicKey:
+			opt = ECDSAPublicKey(key)
+		default:
+			return nil, internal.Errorf("jwt: %T: %w", key, ErrUnsupportedKey)
+		}
+		return construct(func() Algorithm { return newAlg(opt) })
+	}
+}
+
+// construct calls newAlg, returning the error it panics with, if any.
+func construct(newAlg func() Algorithm) (alg Algorithm, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if err, ok = r.(error); !ok {
+				panic(r)
+			}
+		}
+	}()
+	return newAlg(), nil
+}
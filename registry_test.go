@@ -0,0 +1,68 @@
+package jwt_test
+
+import (
+	"crypto/rsa"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAlgorithmByName(t *testing.T) {
+	testCases := []struct {
+		name      string
+		signKey   interface{}
+		verifyKey interface{}
+		err       error
+	}{
+		{"HS256", hmacKey1, string(hmacKey1), nil},
+		{"HS512", hmacKey1, hmacKey1, nil},
+		{"RS256", rsaPrivateKey1, rsaPublicKey1, nil},
+		{"PS384", rsaPrivateKey1, rsaPublicKey1, nil},
+		{"ES256", es256PrivateKey1, es256PublicKey1, nil},
+		{"ES512", es512PrivateKey1, es512PublicKey1, nil},
+		{"ES256K", es256kPrivateKey1, es256kPublicKey1, nil},
+		{"ES256K", es256PrivateKey1, nil, jwt.ErrECDSAInvalidCurve},
+		{"EdDSA", ed25519PrivateKey1, ed25519PublicKey1, nil},
+		{"HS256", rsaPrivateKey1, nil, jwt.ErrUnsupportedKey},
+		{"RS256", es256PrivateKey1, nil, jwt.ErrUnsupportedKey},
+		{"RS256", (*rsa.PrivateKey)(nil), nil, jwt.ErrRSANilPrivKey},
+		{"HS256", []byte{}, nil, jwt.ErrHMACMissingKey},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			newAlg, ok := jwt.AlgorithmByName(tc.name)
+			if !ok {
+				t.Fatalf("jwt.AlgorithmByName(%q) not found", tc.name)
+			}
+			signer, err := newAlg(tc.signKey)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.AlgorithmFactory err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if err != nil {
+				return
+			}
+			if want, got := tc.name, signer.Name(); got != want {
+				t.Errorf("jwt.Algorithm.Name mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			verifier, err := newAlg(tc.verifyKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := jwt.Sign(tp, signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pl testPayload
+			if _, err = jwt.Verify(token, verifier, &pl); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+	for _, name := range []string{"none", "HS1", ""} {
+		if _, ok := jwt.AlgorithmByName(name); ok {
+			t.Errorf("jwt.AlgorithmByName(%q) found", name)
+		}
+	}
+}
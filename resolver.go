@@ -1,7 +1,26 @@
 package jwt
 
+import "context"
+
 // Resolver is an Algorithm that needs to set some variables
 // based on a Header before performing signing and verification.
 type Resolver interface {
 	Resolve(Header) error
 }
+
+// ContextResolver is a Resolver that accepts a context,
+// which is used by VerifyContext for cancelling the resolution.
+type ContextResolver interface {
+	Resolver
+	ResolveContext(context.Context, Header) error
+}
+
+func resolve(ctx context.Context, alg Algorithm, hd Header) error {
+	switch rv := alg.(type) {
+	case ContextResolver:
+		return rv.ResolveContext(ctx, hd)
+	case Resolver:
+		return rv.Resolve(hd)
+	}
+	return nil
+}
@@ -14,11 +14,17 @@ var (
 	// ErrRSANilPubKey is the error for trying to verify a JWT with a nil public key.
 	ErrRSANilPubKey = internal.NewError("jwt: RSA public key is nil")
 	// ErrRSAVerification is the error for an invalid RSA signature.
-	ErrRSAVerification = internal.NewError("jwt: RSA verification failed")
+	ErrRSAVerification = internal.WrapError("jwt: RSA verification failed", ErrSignatureMismatch)
+	// ErrRSAKeyTooSmall is the error for a key shorter than the minimum size, which may be forgeable.
+	ErrRSAKeyTooSmall = internal.NewError("jwt: RSA key is too small")
 
 	_ Algorithm = new(RSASHA)
 )
 
+// DefaultMinRSAKeySize is the minimum bit length, as required by the RFC 7518, of RSA public keys
+// accepted by the RSA-SHA constructors for verifying and by JWKs, unless overridden by the RSAMinKeySize option.
+const DefaultMinRSAKeySize = 2048
+
 // RSAPrivateKey is an option to set a private key to the RSA-SHA algorithm.
 func RSAPrivateKey(priv *rsa.PrivateKey) func(*RSASHA) {
 	return func(rs *RSASHA) {
@@ -33,6 +39,14 @@ func RSAPublicKey(pub *rsa.PublicKey) func(*RSASHA) {
 	}
 }
 
+// RSAMinKeySize is an option to set the minimum bit length of the RSA-SHA algorithm's public key,
+// overriding DefaultMinRSAKeySize, for example, for legacy keys.
+func RSAMinKeySize(bits int) func(*RSASHA) {
+	return func(rs *RSASHA) {
+		rs.minKeySize = bits
+	}
+}
+
 // RSASHA is an algorithm that uses RSA to sign SHA hashes.
 type RSASHA struct {
 	name string
@@ -42,13 +56,21 @@ type RSASHA struct {
 	size int
 	pool *hashPool
 	opts *rsa.PSSOptions
+
+	minKeySize int
 }
 
+// pssVerifyOptions accepts any salt length when verifying RSA-PSS signatures,
+// so tokens signed by older versions of this package, which used the maximum salt length, still verify.
+var pssVerifyOptions = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto}
+
 func newRSASHA(name string, opts []func(*RSASHA), sha crypto.Hash, pss bool) *RSASHA {
 	rs := RSASHA{
 		name: name, // cache name
 		sha:  sha,
 		pool: newHashPool(sha.New),
+
+		minKeySize: DefaultMinRSAKeySize,
 	}
 	for _, opt := range opts {
 		if opt != nil {
@@ -60,18 +82,44 @@ func newRSASHA(name string, opts []func(*RSASHA), sha crypto.Hash, pss bool) *RS
 			panic(ErrRSANilPrivKey)
 		}
 		rs.pub = &rs.priv.PublicKey
+	} else if rs.priv == nil {
+		// Only keys for verifying are checked, since a short public key
+		// is what would let an attacker forge signatures.
+		if err := checkRSAKeySize(rs.pub, rs.minKeySize); err != nil {
+			panic(err)
+		}
 	}
 	rs.size = rs.pub.Size() // cache size
 	if pss {
 		rs.opts = &rsa.PSSOptions{
-			SaltLength: rsa.PSSSaltLengthAuto,
+			SaltLength: rsa.PSSSaltLengthEqualsHash, // as per the RFC 7518
 			Hash:       sha,
 		}
 	}
 	return &rs
 }
 
+// minRSAKeySize returns the minimum key size set by opts.
+func minRSAKeySize(opts []func(*RSASHA)) int {
+	rs := RSASHA{minKeySize: DefaultMinRSAKeySize}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&rs)
+		}
+	}
+	return rs.minKeySize
+}
+
+func checkRSAKeySize(pub *rsa.PublicKey, min int) error {
+	if bits := pub.N.BitLen(); bits < min {
+		return internal.Errorf("jwt: RSA key has %d bits, want at least %d: %w", bits, min, ErrRSAKeyTooSmall)
+	}
+	return nil
+}
+
 // NewRS256 creates a new algorithm using RSA and SHA-256.
+// Like the other RSA-SHA constructors, it panics with ErrRSAKeyTooSmall if it's only given a public key
+// shorter than DefaultMinRSAKeySize or the size set by RSAMinKeySize. Private keys are not checked.
 func NewRS256(opts ...func(*RSASHA)) *RSASHA {
 	return newRSASHA("RS256", opts, crypto.SHA256, false)
 }
@@ -87,6 +135,7 @@ func NewRS512(opts ...func(*RSASHA)) *RSASHA {
 }
 
 // NewPS256 creates a new algorithm using RSA-PSS and SHA-256.
+// Signatures are created with a salt as long as the hash, as per the RFC 7518.
 func NewPS256(opts ...func(*RSASHA)) *RSASHA {
 	return newRSASHA("PS256", opts, crypto.SHA256, true)
 }
@@ -139,7 +188,7 @@ func (rs *RSASHA) Verify(headerPayload, sig []byte) (err error) {
 		return err
 	}
 	if rs.opts != nil {
-		err = rsa.VerifyPSS(rs.pub, rs.sha, sum, sig, rs.opts)
+		err = rsa.VerifyPSS(rs.pub, rs.sha, sum, sig, pssVerifyOptions)
 	} else {
 		err = rsa.VerifyPKCS1v15(rs.pub, rs.sha, sum, sig)
 	}
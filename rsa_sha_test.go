@@ -1,9 +1,12 @@
 package jwt_test
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
-
+	"encoding/base64"
+	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/gbrlsnchs/jwt/v3"
@@ -38,7 +41,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrRSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey1)),
@@ -98,7 +101,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrRSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewRS384(jwt.RSAPrivateKey(rsaPrivateKey1)),
@@ -158,7 +161,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrRSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewRS512(jwt.RSAPrivateKey(rsaPrivateKey1)),
@@ -220,7 +223,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrRSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewPS256(jwt.RSAPrivateKey(rsaPrivateKey1)),
@@ -232,7 +235,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrRSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewPS256(jwt.RSAPrivateKey(rsaPrivateKey1)),
@@ -292,7 +295,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrRSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewPS384(jwt.RSAPrivateKey(rsaPrivateKey1)),
@@ -304,7 +307,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrRSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewPS384(jwt.RSAPrivateKey(rsaPrivateKey1)),
@@ -364,7 +367,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrRSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewPS512(jwt.RSAPrivateKey(rsaPrivateKey1)),
@@ -376,7 +379,7 @@ var (
 			},
 			wantPayload: testPayload{},
 			signErr:     nil,
-			verifyErr:   jwt.ErrRSAVerification,
+			verifyErr:   jwt.ErrAlgValidation,
 		},
 		{
 			alg:       jwt.NewPS512(jwt.RSAPrivateKey(rsaPrivateKey1)),
@@ -458,6 +461,82 @@ func TestNewRSASHA(t *testing.T) {
 	}
 }
 
+func TestPSSSaltLength(t *testing.T) {
+	testCases := []struct {
+		builder func(...func(*jwt.RSASHA)) *jwt.RSASHA
+		hash    crypto.Hash
+	}{
+		{jwt.NewPS256, crypto.SHA256},
+		{jwt.NewPS384, crypto.SHA384},
+		{jwt.NewPS512, crypto.SHA512},
+	}
+	for _, tc := range testCases {
+		funcName := funcName(tc.builder)
+		t.Run(funcName, func(t *testing.T) {
+			token, err := jwt.Sign(tp, tc.builder(jwt.RSAPrivateKey(rsaPrivateKey1)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			i := strings.LastIndexByte(string(token), '.')
+			sig, err := base64.RawURLEncoding.DecodeString(string(token[i+1:]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := tc.hash.New()
+			_, _ = h.Write(token[:i])
+			opts := &rsa.PSSOptions{SaltLength: tc.hash.Size(), Hash: tc.hash}
+			if err = rsa.VerifyPSS(rsaPublicKey1, tc.hash, h.Sum(nil), sig, opts); err != nil {
+				t.Errorf("salt length is not %d: %v", tc.hash.Size(), err)
+			}
+		})
+	}
+}
+
+func TestRSAMinKeySize(t *testing.T) {
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name string
+		opts []func(*jwt.RSASHA)
+		err  error
+	}{
+		{"private key", []func(*jwt.RSASHA){jwt.RSAPrivateKey(small)}, nil},
+		{"private and public keys", []func(*jwt.RSASHA){jwt.RSAPrivateKey(small), jwt.RSAPublicKey(&small.PublicKey)}, nil},
+		{"default public key", []func(*jwt.RSASHA){jwt.RSAPublicKey(&small.PublicKey)}, jwt.ErrRSAKeyTooSmall},
+		{"lowered minimum", []func(*jwt.RSASHA){jwt.RSAPublicKey(&small.PublicKey), jwt.RSAMinKeySize(1024)}, nil},
+		{"raised minimum", []func(*jwt.RSASHA){jwt.RSAPublicKey(rsaPublicKey1), jwt.RSAMinKeySize(3072)}, jwt.ErrRSAKeyTooSmall},
+		{"large enough", []func(*jwt.RSASHA){jwt.RSAPublicKey(rsaPublicKey1)}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+					t.Errorf("jwt.NewPS256 panic = %v, want %v", got, want)
+				}
+			}()
+			jwt.NewPS256(tc.opts...)
+		})
+	}
+
+	t.Run("JWK", func(t *testing.T) {
+		jwk := jwt.JWK{
+			KeyType: "RSA",
+			N:       encodeJWKInt(small.N),
+			E:       encodeJWKInt(big.NewInt(int64(small.E))),
+		}
+		_, err := jwk.NewAlgorithm("RS256")
+		if want, got := jwt.ErrRSAKeyTooSmall, err; !internal.ErrorIs(got, want) {
+			t.Errorf("jwt.JWK.NewAlgorithm err = %v, want %v", got, want)
+		}
+		if _, err = jwk.NewAlgorithm("RS256", jwt.RSAMinKeySize(1024)); err != nil {
+			t.Errorf("jwt.JWK.NewAlgorithm with jwt.RSAMinKeySize lowered: %v", err)
+		}
+	})
+}
+
 func genRSAKeys() (*rsa.PrivateKey, *rsa.PublicKey) {
 	priv, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
@@ -0,0 +1,298 @@
+package jwt
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+var (
+	// ErrSchemaInvalid is the error for a JSON Schema that can't be parsed by SchemaValidator.
+	ErrSchemaInvalid = internal.NewError("jwt: JSON Schema is invalid")
+	// ErrSchemaValidation is the error for claims that don't conform to a JSON Schema.
+	ErrSchemaValidation = internal.NewError("jwt: claims don't conform to JSON Schema")
+)
+
+// schema is a JSON Schema, narrowed down to the validation keywords supported by SchemaValidator.
+type schema struct {
+	Type                 schemaTypes        `json:"type"`
+	Enum                 []interface{}      `json:"enum"`
+	Const                *json.RawMessage   `json:"const"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *json.RawMessage   `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+
+	constValue  interface{}
+	pattern     *regexp.Regexp
+	noAdditions bool
+	additional  *schema
+}
+
+var schemaTypeNames = map[string]struct{}{
+	"object": {}, "array": {}, "string": {}, "number": {}, "integer": {}, "boolean": {}, "null": {},
+}
+
+// schemaTypes is the "type" keyword, which is either a single type or an array of them.
+type schemaTypes []string
+
+func (st *schemaTypes) UnmarshalJSON(b []byte) error {
+	var typ string
+	if err := json.Unmarshal(b, &typ); err == nil {
+		*st = schemaTypes{typ}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(st))
+}
+
+// supportedKeywords are the keywords implemented by SchemaValidator. Annotations, which never
+// affect validation, are also accepted.
+var supportedKeywords = map[string]struct{}{
+	"type": {}, "enum": {}, "const": {}, "required": {}, "properties": {}, "additionalProperties": {},
+	"items": {}, "minItems": {}, "maxItems": {}, "minLength": {}, "maxLength": {}, "pattern": {},
+	"minimum": {}, "maximum": {},
+	"$schema": {}, "$id": {}, "$comment": {}, "title": {}, "description": {}, "default": {}, "examples": {},
+}
+
+// SchemaValidator validates all claims of a token's payload, including private ones, against a JSON Schema,
+// so claim policies can be expressed declaratively. It's run with the ValidateRawPayload option,
+// against the payload as it was decoded:
+//
+//	_, err := jwt.Verify(token, alg, &pl, jwt.ValidateRawPayload(jwt.SchemaValidator(schemaJSON)))
+//
+// Only the following validation keywords are supported: "type", "enum", "const", "required", "properties",
+// "additionalProperties", "items", "minItems", "maxItems", "minLength", "maxLength", "pattern", "minimum"
+// and "maximum". Annotations, such as "title" and "description", are accepted too.
+//
+// It panics with ErrSchemaInvalid if schemaJSON can't be parsed or uses any other keyword, so a schema
+// is never partially enforced. The returned error wraps ErrSchemaValidation and lists every violation found.
+func SchemaValidator(schemaJSON []byte) RawValidator {
+	if err := checkKeywords(schemaJSON); err != nil {
+		panic(internal.Errorf("jwt: %v: %w", err, ErrSchemaInvalid))
+	}
+	var sc schema
+	if err := json.Unmarshal(schemaJSON, &sc); err != nil {
+		panic(internal.Errorf("jwt: %v: %w", err, ErrSchemaInvalid))
+	}
+	if err := sc.compile(); err != nil {
+		panic(internal.Errorf("jwt: %v: %w", err, ErrSchemaInvalid))
+	}
+	return func(payload []byte) error {
+		var claims interface{}
+		if err := JSONUnmarshal(payload, &claims); err != nil {
+			return malformed(err)
+		}
+		var violations []string
+		sc.validate(claims, "", &violations)
+		if len(violations) > 0 {
+			return internal.Errorf("jwt: %s: %w", strings.Join(violations, "; "), ErrSchemaValidation)
+		}
+		return nil
+	}
+}
+
+// checkKeywords checks that a schema and its subschemas only use supported keywords.
+func checkKeywords(schemaJSON []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(schemaJSON, &members); err != nil {
+		return err
+	}
+	for name := range members {
+		if _, ok := supportedKeywords[name]; !ok {
+			return internal.Errorf("jwt: %q keyword is not supported", name)
+		}
+	}
+	subschemas := []json.RawMessage{members["items"]}
+	if ap := members["additionalProperties"]; isJSONObject(ap) {
+		subschemas = append(subschemas, ap)
+	}
+	if props := members["properties"]; props != nil {
+		var sub map[string]json.RawMessage
+		if err := json.Unmarshal(props, &sub); err != nil {
+			return err
+		}
+		for _, s := range sub {
+			subschemas = append(subschemas, s)
+		}
+	}
+	for _, sub := range subschemas {
+		if sub == nil {
+			continue
+		}
+		if err := checkKeywords(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compile prepares the schema and its subschemas for validation.
+func (sc *schema) compile() (err error) {
+	for _, typ := range sc.Type {
+		if _, ok := schemaTypeNames[typ]; !ok {
+			return internal.Errorf("jwt: %q type is not supported", typ)
+		}
+	}
+	if sc.Const != nil {
+		if err = json.Unmarshal(*sc.Const, &sc.constValue); err != nil {
+			return err
+		}
+	}
+	if sc.Pattern != "" {
+		if sc.pattern, err = regexp.Compile(sc.Pattern); err != nil {
+			return err
+		}
+	}
+	if sc.AdditionalProperties != nil {
+		var allowed bool
+		if err = json.Unmarshal(*sc.AdditionalProperties, &allowed); err == nil {
+			sc.noAdditions = !allowed
+		} else if err = json.Unmarshal(*sc.AdditionalProperties, &sc.additional); err != nil {
+			return err
+		}
+	}
+	subschemas := []*schema{sc.Items, sc.additional}
+	for _, sub := range sc.Properties {
+		subschemas = append(subschemas, sub)
+	}
+	for _, sub := range subschemas {
+		if sub == nil {
+			continue
+		}
+		if err = sub.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate appends violations of the schema by v, located at path, to violations.
+func (sc *schema) validate(v interface{}, path string, violations *[]string) {
+	report := func(msg string) {
+		p := path
+		if p == "" {
+			p = "/"
+		}
+		*violations = append(*violations, p+": "+msg)
+	}
+	if len(sc.Type) > 0 && !sc.hasType(v) {
+		report("want " + strings.Join(sc.Type, " or "))
+		return
+	}
+	if sc.Enum != nil && !containsValue(sc.Enum, v) {
+		report("not in enum")
+	}
+	if sc.Const != nil && !reflect.DeepEqual(sc.constValue, v) {
+		report("mismatches const")
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, name := range sc.Required {
+			if _, ok := v[name]; !ok {
+				report("missing " + strconv.Quote(name))
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names) // for deterministic errors
+		for _, name := range names {
+			sub, ok := sc.Properties[name]
+			switch {
+			case ok:
+			case sc.noAdditions:
+				report("unexpected " + strconv.Quote(name))
+				continue
+			case sc.additional != nil:
+				sub = sc.additional
+			default:
+				// As per JSON Schema, other properties are allowed when "additionalProperties" is absent.
+				// Keywords that could still constrain them, such as "patternProperties", are rejected
+				// by checkKeywords, so nothing is skipped here.
+				continue
+			}
+			sub.validate(v[name], path+"/"+name, violations)
+		}
+	case []interface{}:
+		if sc.MinItems != nil && len(v) < *sc.MinItems {
+			report("want at least " + strconv.Itoa(*sc.MinItems) + " items")
+		}
+		if sc.MaxItems != nil && len(v) > *sc.MaxItems {
+			report("want at most " + strconv.Itoa(*sc.MaxItems) + " items")
+		}
+		if sc.Items != nil {
+			for i, item := range v {
+				sc.Items.validate(item, path+"/"+strconv.Itoa(i), violations)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(v)
+		if sc.MinLength != nil && n < *sc.MinLength {
+			report("want at least " + strconv.Itoa(*sc.MinLength) + " characters")
+		}
+		if sc.MaxLength != nil && n > *sc.MaxLength {
+			report("want at most " + strconv.Itoa(*sc.MaxLength) + " characters")
+		}
+		if sc.pattern != nil && !sc.pattern.MatchString(v) {
+			report("mismatches pattern " + strconv.Quote(sc.Pattern))
+		}
+	case float64:
+		if sc.Minimum != nil && v < *sc.Minimum {
+			report("want at least " + strconv.FormatFloat(*sc.Minimum, 'g', -1, 64))
+		}
+		if sc.Maximum != nil && v > *sc.Maximum {
+			report("want at most " + strconv.FormatFloat(*sc.Maximum, 'g', -1, 64))
+		}
+	}
+}
+
+func (sc *schema) hasType(v interface{}) bool {
+	for _, typ := range sc.Type {
+		var ok bool
+		switch typ {
+		case "object":
+			_, ok = v.(map[string]interface{})
+		case "array":
+			_, ok = v.([]interface{})
+		case "string":
+			_, ok = v.(string)
+		case "number":
+			_, ok = v.(float64)
+		case "integer":
+			f, isNumber := v.(float64)
+			ok = isNumber && f == math.Trunc(f)
+		case "boolean":
+			_, ok = v.(bool)
+		case "null":
+			ok = v == nil
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, value := range values {
+		if reflect.DeepEqual(value, v) {
+			return true
+		}
+	}
+	return false
+}
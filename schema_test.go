@@ -0,0 +1,136 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+const claimsSchema = `{
+	"type": "object",
+	"required": ["sub", "roles"],
+	"properties": {
+		"sub": {"type": "string", "pattern": "^user:[0-9]+$"},
+		"aud": {"type": ["string", "array"]},
+		"roles": {"type": "array", "minItems": 1, "items": {"enum": ["admin", "reader"]}},
+		"level": {"type": "integer", "minimum": 1, "maximum": 3},
+		"tenant": {"const": "acme"}
+	}
+}`
+
+func TestSchemaValidator(t *testing.T) {
+	vd := jwt.SchemaValidator([]byte(claimsSchema))
+	testCases := []struct {
+		name       string
+		payload    string
+		err        error
+		violations []string
+	}{
+		{"valid", `{"sub":"user:1","aud":["a","b"],"roles":["admin"],"level":2,"tenant":"acme"}`, nil, nil},
+		{"missing claims", `{"iss":"iss"}`, jwt.ErrSchemaValidation, []string{`/: missing "sub"`, `/: missing "roles"`}},
+		{
+			"invalid claims",
+			`{"sub":"admin","roles":["owner"],"level":1.5,"tenant":"other"}`,
+			jwt.ErrSchemaValidation,
+			[]string{`/level: want integer`, `/roles/0: not in enum`, `/sub: mismatches pattern`, `/tenant: mismatches const`},
+		},
+		{"too few items", `{"sub":"user:1","roles":[],"level":4}`, jwt.ErrSchemaValidation, []string{`/level: want at most 3`, `/roles: want at least 1 items`}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pl jwt.Payload
+			token, err := jwt.Sign(json.RawMessage(tc.payload), jwt.NewHS256(hmacKey1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = jwt.Verify(token, jwt.NewHS256(hmacKey1), &pl, jwt.ValidateRawPayload(vd))
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.SchemaValidator err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			for _, v := range tc.violations {
+				if !strings.Contains(err.Error(), v) {
+					t.Errorf("jwt.SchemaValidator err %q doesn't contain %q", err, v)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaValidatorAdditionalProperties(t *testing.T) {
+	vd := jwt.SchemaValidator([]byte(`{"properties":{"iss":{}},"additionalProperties":false}`))
+	if err := vd([]byte(`{"iss":"iss"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := jwt.ErrSchemaValidation, vd([]byte(`{"iss":"iss","sub":"sub"}`)); !internal.ErrorIs(got, want) {
+		t.Errorf("jwt.SchemaValidator err = %v, want %v", got, want)
+	}
+}
+
+func TestSchemaValidatorRawPayload(t *testing.T) {
+	// Claims of a custom struct are validated, as well as the "aud" claim as it was encoded.
+	type claims struct {
+		jwt.Payload
+		Tenant string `json:"tenant"`
+	}
+	vd := jwt.SchemaValidator([]byte(`{"required":["tenant"],"properties":{"aud":{"type":"string"}}}`))
+	testCases := []struct {
+		payload string
+		err     error
+	}{
+		{`{"aud":"aud","tenant":"acme"}`, nil},
+		{`{"aud":["aud"],"tenant":"acme"}`, jwt.ErrSchemaValidation},
+		{`{"aud":"aud"}`, jwt.ErrSchemaValidation},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.payload, func(t *testing.T) {
+			token, err := jwt.Sign(json.RawMessage(tc.payload), jwt.NewHS256(hmacKey1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var c claims
+			_, err = jwt.Verify(token, jwt.NewHS256(hmacKey1), &c, jwt.ValidateRawPayload(vd))
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSchemaValidatorInvalid(t *testing.T) {
+	for _, schema := range []string{
+		`{"type":1}`,
+		`{"type":"int"}`,
+		`{"pattern":"("}`,
+		`[]`,
+		`{"anyOf":[{"type":"string"}]}`,
+		`{"oneOf":[{"type":"string"}]}`,
+		`{"$ref":"#/definitions/claims"}`,
+		`{"properties":{"sub":{"format":"email"}}}`,
+		`{"properties":{"exp":{"exclusiveMinimum":0}}}`,
+		`{"items":{"uniqueItems":true}}`,
+		`{"additionalProperties":{"format":"uri"}}`,
+		`{"patternProperties":{"^x-":{}}}`,
+	} {
+		t.Run(schema, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if want, got := jwt.ErrSchemaInvalid, err; !internal.ErrorIs(got, want) {
+					t.Errorf("jwt.SchemaValidator panic = %v, want %v", got, want)
+				}
+			}()
+			_ = jwt.SchemaValidator([]byte(schema))
+		})
+	}
+}
+
+func TestSchemaValidatorAnnotations(t *testing.T) {
+	vd := jwt.SchemaValidator([]byte(`{"$schema":"http://json-schema.org/draft-07/schema#","title":"claims",` +
+		`"properties":{"sub":{"description":"user","type":"string"}}}`))
+	if err := vd([]byte(`{"sub":"sub"}`)); err != nil {
+		t.Fatal(err)
+	}
+}
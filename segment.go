@@ -0,0 +1,15 @@
+package jwt
+
+import "encoding/base64"
+
+// EncodeSegment encodes b the same way JWT segments are encoded when signing,
+// that is, using the unpadded Base64URL encoding as per the RFC 7515.
+func EncodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeSegment decodes a segment encoded with the unpadded Base64URL encoding.
+// Unlike Verify, it doesn't tolerate padding, and it rejects the standard alphabet.
+func DecodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
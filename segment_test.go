@@ -0,0 +1,40 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSegment(t *testing.T) {
+	testCases := []struct {
+		enc     string
+		dec     []byte
+		wantErr bool
+	}{
+		{"", []byte{}, false},
+		{"AA", []byte{0}, false},
+		{"-_8", []byte{0xfb, 0xff}, false},
+		{"eyJhbGciOiJIUzI1NiJ9", []byte(`{"alg":"HS256"}`), false},
+		{"+/8", nil, true},
+		{"AA==", nil, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.enc, func(t *testing.T) {
+			dec, err := jwt.DecodeSegment(tc.enc)
+			if want, got := tc.wantErr, err != nil; got != want {
+				t.Fatalf("jwt.DecodeSegment err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if err != nil {
+				return
+			}
+			if want, got := tc.dec, dec; !cmp.Equal(got, want) {
+				t.Errorf("jwt.DecodeSegment mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.enc, jwt.EncodeSegment(dec); got != want {
+				t.Errorf("jwt.EncodeSegment mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+// ErrSelfTest is the error for when an algorithm fails a self-test.
+var ErrSelfTest = internal.NewError("jwt: self-test failed")
+
+// selfTestInput is the signing input used by SelfTest.
+var selfTestInput = []byte("eyJhbGciOiJub25lIn0.eyJzdWIiOiJzZWxmLXRlc3QifQ")
+
+// hmacVectors are the known answers for the test case 2 of the RFC 4231.
+var hmacVectors = []struct {
+	alg *HMACSHA
+	sig string
+}{
+	{NewHS256([]byte("Jefe")), "W9zBRr9gdU5qBCQmCJV1x1oAPwidJzmDnexYuWTsOEM"},
+	{NewHS384([]byte("Jefe")), "r0XS43ZIQDFhf3jStYprG5x-9GT1oBtH5C7Dc2MiRF6OIkDKXmnix4syOez6shZJ"},
+	{NewHS512([]byte("Jefe")), "Fkt6e_z4GeLjlfvnO1bgo4e9ZCIugx_WECcM1-olBVSXWL91wFqZSm0DT2X48Ob9yuqxo01Ka0tjbgcKOLznNw"},
+}
+
+// SelfTest checks that alg is functional by signing a fixed input, verifying the signature
+// and making sure verification fails for a tampered input.
+// The algorithm must be able to both sign and verify, so it needs a private key when applicable.
+// The unsecured algorithm returned by None always fails, since it accepts tampered inputs.
+func SelfTest(alg Algorithm) error {
+	sig, err := alg.Sign(selfTestInput)
+	if err != nil {
+		return internal.Errorf("jwt: %s: %v: %w", alg.Name(), err, ErrSelfTest)
+	}
+	enc := make([]byte, base64.RawURLEncoding.EncodedLen(len(sig)))
+	base64.RawURLEncoding.Encode(enc, sig)
+	if err = alg.Verify(selfTestInput, enc); err != nil {
+		return internal.Errorf("jwt: %s: %v: %w", alg.Name(), err, ErrSelfTest)
+	}
+	tampered := append([]byte(nil), selfTestInput...)
+	tampered[len(tampered)-1] ^= 1
+	if alg.Verify(tampered, enc) == nil {
+		return internal.Errorf("jwt: %s: tampered input verified: %w", alg.Name(), ErrSelfTest)
+	}
+	return nil
+}
+
+// SelfTestAll runs known-answer tests for the deterministic HMAC-SHA algorithms,
+// using the test vectors from the RFC 4231.
+//
+// Algorithms that need asymmetric keys can't be covered by hardcoded vectors,
+// since their keys are provided by the caller, so their instances should be checked with SelfTest instead.
+func SelfTestAll() error {
+	data := []byte("what do ya want for nothing?")
+	for _, v := range hmacVectors {
+		sig, err := v.alg.Sign(data)
+		if err != nil {
+			return internal.Errorf("jwt: %s: %v: %w", v.alg.Name(), err, ErrSelfTest)
+		}
+		if want, _ := base64.RawURLEncoding.DecodeString(v.sig); !bytes.Equal(sig, want) {
+			return internal.Errorf("jwt: %s: signature mismatches known answer: %w", v.alg.Name(), ErrSelfTest)
+		}
+		if err = SelfTest(v.alg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
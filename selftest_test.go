@@ -0,0 +1,42 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+type brokenAlgorithm struct{ jwt.Algorithm }
+
+func (brokenAlgorithm) Verify(_, _ []byte) error { return nil }
+
+func TestSelfTest(t *testing.T) {
+	testCases := []struct {
+		alg jwt.Algorithm
+		err error
+	}{
+		{jwt.NewHS256([]byte(hmacKey1)), nil},
+		{jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey1)), nil},
+		{jwt.NewPS384(jwt.RSAPrivateKey(rsaPrivateKey1)), nil},
+		{jwt.NewES512(jwt.ECDSAPrivateKey(es512PrivateKey1)), nil},
+		{jwt.NewES256(jwt.ECDSAPublicKey(es256PublicKey1)), jwt.ErrSelfTest},
+		{jwt.None(), jwt.ErrSelfTest},
+		{brokenAlgorithm{jwt.NewHS256([]byte(hmacKey1))}, jwt.ErrSelfTest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.alg.Name(), func(t *testing.T) {
+			err := jwt.SelfTest(tc.alg)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.SelfTest err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestSelfTestAll(t *testing.T) {
+	if err := jwt.SelfTestAll(); err != nil {
+		t.Fatal(err)
+	}
+}
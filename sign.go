@@ -1,8 +1,9 @@
 package jwt
 
 import (
+	"crypto"
 	"encoding/base64"
-	"encoding/json"
+	"io"
 
 	"github.com/gbrlsnchs/jwt/v3/internal"
 )
@@ -18,44 +19,132 @@ func ContentType(cty string) SignOption {
 }
 
 // KeyID sets the "kid" claim for a Header before signing.
+// When verifying, the "kid" claim is available for resolvers and in the Header returned by Verify.
 func KeyID(kid string) SignOption {
 	return func(hd *Header) {
 		hd.KeyID = kid
 	}
 }
 
+// ThumbprintKeyID sets the "kid" claim for a Header before signing to the JWK thumbprint of key,
+// as computed by Thumbprint, so key IDs don't need to be assigned manually.
+// Like constructors of algorithms, it panics if key is not supported.
+func ThumbprintKeyID(key crypto.PublicKey) SignOption {
+	kid, err := Thumbprint(key)
+	if err != nil {
+		panic(err)
+	}
+	return KeyID(kid)
+}
+
+// Type sets the "typ" claim for a Header before signing, for example, "at+jwt" for
+// OAuth 2.0 access tokens, as per the RFC 9068. If not set, "JWT" is used.
+func Type(typ string) SignOption {
+	return func(hd *Header) {
+		hd.Type = typ
+	}
+}
+
 // Sign signs a payload with alg.
 func Sign(payload interface{}, alg Algorithm, opts ...SignOption) ([]byte, error) {
-	var hd Header
-	for _, opt := range opts {
-		opt(&hd)
+	hb, pb, err := marshal(payload, alg, opts)
+	if err != nil {
+		return nil, err
 	}
-	if rv, ok := alg.(Resolver); ok {
-		if err := rv.Resolve(hd); err != nil {
-			return nil, internal.Errorf("jwt: failed to resolve: %w", err)
-		}
+	return encodeAndSign(hb, pb, alg)
+}
+
+// SignTo is like Sign, but writes the token to w instead of returning it.
+func SignTo(w io.Writer, payload interface{}, alg Algorithm, opts ...SignOption) error {
+	hb, pb, err := marshal(payload, alg, opts)
+	if err != nil {
+		return err
 	}
-	// Override some values or set them if empty.
-	hd.Algorithm = alg.Name()
-	hd.Type = "JWT"
-	// Marshal the header part of the JWT.
-	hb, err := json.Marshal(hd)
+	token, err := encodeAndSign(hb, pb, alg)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	_, err = w.Write(token)
+	return err
+}
 
+// EstimateSize returns the length of the token Sign would return for the same arguments,
+// so payloads too large for, say, a cookie can be rejected before signing.
+// The signature's length is computed from alg's Size, so nothing is signed, except for algorithms
+// whose size is unknown, for which Size returns 0, and whose signature has to be computed and measured.
+func EstimateSize(payload interface{}, alg Algorithm, opts ...SignOption) (int, error) {
+	hb, pb, err := marshal(payload, alg, opts)
+	if err != nil {
+		return 0, err
+	}
+	enc := base64.RawURLEncoding
+	size := enc.EncodedLen(len(hb)) + 1 + enc.EncodedLen(len(pb)) + 1
+	if alg.Size() > 0 {
+		return size + enc.EncodedLen(alg.Size()), nil
+	}
+	token, err := encodeAndSign(hb, pb, alg)
+	if err != nil {
+		return 0, err
+	}
+	return len(token), nil
+}
+
+// marshal marshals the header and payload parts of a JWT.
+func marshal(payload interface{}, alg Algorithm, opts []SignOption) (hb, pb []byte, err error) {
+	hd, err := signHeader(alg, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Marshal the header part of the JWT.
+	if hb, err = marshalHeader(hd); err != nil {
+		return nil, nil, err
+	}
+	// Marshal the claims part of the JWT.
+	if pb, err = marshalPayload(payload); err != nil {
+		return nil, nil, err
+	}
+	return hb, pb, nil
+}
+
+// marshalPayload marshals payload along with its private claims.
+func marshalPayload(payload interface{}) ([]byte, error) {
 	if payload == nil {
 		payload = Payload{}
 	}
-	// Marshal the claims part of the JWT.
-	pb, err := json.Marshal(payload)
+	pb, err := JSONMarshal(payload)
 	if err != nil {
 		return nil, err
 	}
 	if !isJSONObject(pb) {
 		return nil, ErrNotJSONObject
 	}
+	if pc := privateClaims(payload); len(pc) > 0 {
+		return appendMembers(pb, pc, registeredClaims)
+	}
+	return pb, nil
+}
+
+// signHeader builds the header for signing with alg, resolving alg if needed.
+func signHeader(alg Algorithm, opts []SignOption) (Header, error) {
+	var hd Header
+	for _, opt := range opts {
+		opt(&hd)
+	}
+	if rv, ok := alg.(Resolver); ok {
+		if err := rv.Resolve(hd); err != nil {
+			return hd, internal.Errorf("jwt: failed to resolve: %w", err)
+		}
+	}
+	// Override some values or set them if empty.
+	hd.Algorithm = alg.Name()
+	if hd.Type == "" {
+		hd.Type = "JWT"
+	}
+	return hd, nil
+}
 
+// encodeAndSign encodes the header and payload parts of a JWT and appends their signature.
+func encodeAndSign(hb, pb []byte, alg Algorithm) ([]byte, error) {
 	enc := base64.RawURLEncoding
 	h64len := enc.EncodedLen(len(hb))
 	p64len := enc.EncodedLen(len(pb))
@@ -70,6 +159,10 @@ func Sign(payload interface{}, alg Algorithm, opts ...SignOption) ([]byte, error
 		return nil, err
 	}
 	token[h64len+1+p64len] = '.'
-	enc.Encode(token[h64len+1+p64len+1:], sig)
+	sigStart := h64len + 1 + p64len + 1
+	if n := enc.EncodedLen(len(sig)); n != sig64len { // size is unknown or inexact, as for a Signer
+		token = append(token[:sigStart:sigStart], make([]byte, n)...)
+	}
+	enc.Encode(token[sigStart:], sig)
 	return token, nil
 }
@@ -1,8 +1,11 @@
 package jwt_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/gbrlsnchs/jwt/v3"
 	"github.com/gbrlsnchs/jwt/v3/internal"
@@ -109,6 +112,12 @@ func TestSign(t *testing.T) {
 			opts:    nil,
 			err:     nil,
 		},
+		{
+			payload: nil,
+			alg:     jwt.NewEd25519(jwt.Ed25519PublicKey(ed25519PublicKey1)),
+			opts:    nil,
+			err:     jwt.ErrEd25519NilPrivKey,
+		},
 		{
 			payload: 0xDEAD,
 			alg:     jwt.NewHS256([]byte("secret")),
@@ -158,3 +167,180 @@ func TestSign(t *testing.T) {
 		})
 	}
 }
+
+func TestSignKeyID(t *testing.T) {
+	keys := map[string]jwt.Algorithm{
+		"key-1": jwt.NewHS256(hmacKey1),
+		"key-2": jwt.NewHS256(hmacKey2),
+	}
+	for kid, alg := range keys {
+		t.Run(kid, func(t *testing.T) {
+			token, err := jwt.Sign(jwt.Payload{}, alg, jwt.KeyID(kid))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rv := &jwtutil.Resolver{New: func(hd jwt.Header) (jwt.Algorithm, error) {
+				return keys[hd.KeyID], nil
+			}}
+			var pl jwt.Payload
+			hd, err := jwt.Verify(token, rv, &pl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := jwt.Header{Algorithm: "HS256", KeyID: kid, Type: "JWT"}
+			if got := hd; !cmp.Equal(got, want) {
+				t.Errorf("jwt.Verify header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestSignTo(t *testing.T) {
+	testCases := []struct {
+		alg jwt.Algorithm
+	}{
+		{jwt.NewHS256(hmacKey1)},
+		{jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey1))},
+		{jwt.NewEd25519(jwt.Ed25519PrivateKey(ed25519PrivateKey1))},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.alg.Name(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := jwt.SignTo(&buf, tp, tc.alg, jwt.KeyID("kid")); err != nil {
+				t.Fatal(err)
+			}
+			// These algorithms are deterministic, so results must match.
+			want, err := jwt.Sign(tp, tc.alg, jwt.KeyID("kid"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.Bytes(); string(got) != string(want) {
+				t.Errorf("jwt.SignTo mismatch (-want +got):\n%s", cmp.Diff(string(want), string(got)))
+			}
+		})
+	}
+	t.Run("write error", func(t *testing.T) {
+		err := jwt.SignTo(errWriter{}, tp, jwt.NewHS256(hmacKey1))
+		if want, got := testErr, err; got != want {
+			t.Errorf("jwt.SignTo err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+}
+
+func TestEstimateSize(t *testing.T) {
+	testCases := []struct {
+		alg jwt.Algorithm
+	}{
+		{jwt.None()},
+		{jwt.NewHS384(hmacKey1)},
+		{jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey1))},
+		{jwt.NewPS512(jwt.RSAPrivateKey(rsaPrivateKey1))},
+		{jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey1))},
+		{jwt.NewES512(jwt.ECDSAPrivateKey(es512PrivateKey1))},
+		{jwt.NewEd25519(jwt.Ed25519PrivateKey(ed25519PrivateKey1))},
+		{sizeless{jwt.NewHS256(hmacKey1)}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.alg.Name(), func(t *testing.T) {
+			got, err := jwt.EstimateSize(tp, tc.alg, jwt.KeyID("kid"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := jwt.Sign(tp, tc.alg, jwt.KeyID("kid"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := len(token); got != want {
+				t.Errorf("jwt.EstimateSize mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+// sizeless is an algorithm that doesn't know its signature size in advance.
+type sizeless struct{ jwt.Algorithm }
+
+func (sizeless) Size() int { return 0 }
+
+func TestSignDeterministic(t *testing.T) {
+	newPayload := func() jwt.Payload {
+		pl := jwt.Payload{
+			Issuer:   "iss",
+			Subject:  "sub",
+			Audience: jwt.Audience{"aud"},
+			IssuedAt: jwt.NumericDate(time.Unix(1, 0)),
+			JWTID:    "jti",
+		}
+		for _, name := range []string{"zeta", "alpha", "mu", "beta", "omega", "gamma"} {
+			if err := pl.Set(name, name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return pl
+	}
+	extra := func(hd *jwt.Header) {
+		hd.Extra = map[string]json.RawMessage{"z": json.RawMessage(`1`), "a": json.RawMessage(`2`), "m": json.RawMessage(`3`)}
+	}
+	hs256 := jwt.NewHS256(hmacKey1)
+	want, err := jwt.Sign(newPayload(), hs256, jwt.KeyID("kid"), extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		got, err := jwt.Sign(newPayload(), hs256, jwt.KeyID("kid"), extra)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Fatalf("jwt.Sign mismatch (-want +got):\n%s", cmp.Diff(string(want), string(got)))
+		}
+	}
+	raw, err := jwt.Decode(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHeader := `{"alg":"HS256","kid":"kid","typ":"JWT","a":2,"m":3,"z":1}`
+	if got := string(raw.Header()); got != wantHeader {
+		t.Errorf("header mismatch (-want +got):\n%s", cmp.Diff(wantHeader, got))
+	}
+	wantPayload := `{"iss":"iss","sub":"sub","aud":"aud","iat":1,"jti":"jti",` +
+		`"alpha":"alpha","beta":"beta","gamma":"gamma","mu":"mu","omega":"omega","zeta":"zeta"}`
+	if got := string(raw.Payload()); got != wantPayload {
+		t.Errorf("payload mismatch (-want +got):\n%s", cmp.Diff(wantPayload, got))
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, testErr }
+
+func TestSignType(t *testing.T) {
+	testCases := []struct {
+		opts []jwt.SignOption
+		want string
+	}{
+		{nil, `{"alg":"HS256","typ":"JWT"}`},
+		{[]jwt.SignOption{jwt.Type("at+jwt")}, `{"alg":"HS256","typ":"at+jwt"}`},
+		{[]jwt.SignOption{jwt.Type("")}, `{"alg":"HS256","typ":"JWT"}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			alg := jwt.NewHS256(hmacKey1)
+			token, err := jwt.Sign(jwt.Payload{}, alg, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rt, err := jwt.Decode(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, got := tc.want, string(rt.Header()); got != want {
+				t.Errorf("jwt.Sign header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			var pl jwt.Payload
+			if _, err = jwt.Verify(token, alg, &pl, jwt.ValidateType("JWT", "at+jwt")); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
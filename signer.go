@@ -0,0 +1,87 @@
+package jwt
+
+import (
+	"crypto"
+
+	"github.com/gbrlsnchs/jwt/v3/internal"
+)
+
+var errNotSupported = internal.NewError("jwt: operation not supported")
+
+// Signer computes signatures for an algorithm whose key is kept elsewhere,
+// for example, in a KMS or an HSM. Unlike Algorithm, it doesn't need to know the size
+// of its signatures nor to verify them.
+type Signer interface {
+	// Name returns the algorithm's name, which is set as the "alg" header parameter.
+	Name() string
+	// Sign returns the signature of signingInput, which is the encoded header and payload.
+	Sign(signingInput []byte) (sig []byte, err error)
+}
+
+// DigestSigner is a Signer that signs pre-hashed signing inputs, as required by some KMS and HSM APIs.
+// When it's passed to SignWith, the signing input is hashed with HashFunc and its digest
+// is passed to SignDigest instead of the signing input being passed to Sign.
+type DigestSigner interface {
+	Signer
+	// HashFunc returns the hash the algorithm signs, for example, crypto.SHA256 for "RS256".
+	HashFunc() crypto.Hash
+	// SignDigest returns the signature of a digest computed with HashFunc.
+	SignDigest(digest []byte) (sig []byte, err error)
+}
+
+// Verifier verifies signatures for an algorithm whose key is kept elsewhere,
+// for example, by calling a remote service.
+type Verifier interface {
+	// Name returns the algorithm's name, which must match the "alg" header parameter.
+	Name() string
+	// Verify verifies the decoded signature sig of signingInput.
+	// Errors for invalid signatures should wrap ErrSignatureMismatch.
+	Verify(signingInput, sig []byte) error
+}
+
+// SignWith is like Sign, but delegates computing the signature to s,
+// while the header, the payload and the token are still built by Sign.
+func SignWith(payload interface{}, s Signer, opts ...SignOption) ([]byte, error) {
+	return Sign(payload, signerAlgorithm{s}, opts...)
+}
+
+// VerifyWith is like Verify, but delegates verifying the signature to v,
+// while the token is still decoded and checked by Verify.
+func VerifyWith(token []byte, v Verifier, payload interface{}, opts ...VerifyOption) (Header, error) {
+	return Verify(token, verifierAlgorithm{v}, payload, opts...)
+}
+
+// signerAlgorithm is an Algorithm that can only sign.
+type signerAlgorithm struct{ Signer }
+
+func (sa signerAlgorithm) Sign(headerPayload []byte) ([]byte, error) {
+	ds, ok := sa.Signer.(DigestSigner)
+	if !ok {
+		return sa.Signer.Sign(headerPayload)
+	}
+	hh := ds.HashFunc().New()
+	if _, err := hh.Write(headerPayload); err != nil {
+		return nil, err
+	}
+	return ds.SignDigest(hh.Sum(nil))
+}
+
+func (signerAlgorithm) Size() int { return 0 }
+
+func (signerAlgorithm) Verify(_, _ []byte) error { return errNotSupported }
+
+// verifierAlgorithm is an Algorithm that can only verify.
+type verifierAlgorithm struct{ v Verifier }
+
+func (va verifierAlgorithm) Name() string { return va.v.Name() }
+
+func (verifierAlgorithm) Sign(_ []byte) ([]byte, error) { return nil, errNotSupported }
+
+func (verifierAlgorithm) Size() int { return 0 }
+
+func (va verifierAlgorithm) Verify(headerPayload, sig []byte) (err error) {
+	if sig, err = internal.DecodeToBytes(sig); err != nil {
+		return err
+	}
+	return va.v.Verify(headerPayload, sig)
+}
@@ -0,0 +1,116 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/gbrlsnchs/jwt/v3"
+	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/google/go-cmp/cmp"
+)
+
+// kms simulates a remote service holding an RSA key.
+type kms struct {
+	priv *rsa.PrivateKey
+}
+
+func (kms) Name() string { return "RS256" }
+
+func (k kms) Sign(signingInput []byte) ([]byte, error) {
+	sum := sha256.Sum256(signingInput)
+	return rsa.SignPKCS1v15(rand.Reader, k.priv, crypto.SHA256, sum[:])
+}
+
+func (k kms) Verify(signingInput, sig []byte) error {
+	sum := sha256.Sum256(signingInput)
+	if err := rsa.VerifyPKCS1v15(&k.priv.PublicKey, crypto.SHA256, sum[:], sig); err != nil {
+		return jwt.ErrSignatureMismatch
+	}
+	return nil
+}
+
+// digestKMS simulates a remote service that only signs digests.
+type digestKMS struct {
+	kms
+	hash crypto.Hash
+}
+
+func (digestKMS) Sign([]byte) ([]byte, error) { return nil, testErr }
+
+func (k digestKMS) HashFunc() crypto.Hash { return k.hash }
+
+func (k digestKMS) SignDigest(digest []byte) ([]byte, error) {
+	return rsa.SignPKCS1v15(rand.Reader, k.priv, k.hash, digest)
+}
+
+func (k digestKMS) Name() string {
+	return map[crypto.Hash]string{crypto.SHA256: "RS256", crypto.SHA512: "RS512"}[k.hash]
+}
+
+func TestSignWithDigest(t *testing.T) {
+	testCases := []struct {
+		signer   jwt.Signer
+		verifier jwt.Algorithm
+	}{
+		{digestKMS{kms{rsaPrivateKey1}, crypto.SHA256}, jwt.NewRS256(jwt.RSAPublicKey(rsaPublicKey1))},
+		{digestKMS{kms{rsaPrivateKey1}, crypto.SHA512}, jwt.NewRS512(jwt.RSAPublicKey(rsaPublicKey1))},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.signer.Name(), func(t *testing.T) {
+			token, err := jwt.SignWith(tp, tc.signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pl testPayload
+			if _, err = jwt.Verify(token, tc.verifier, &pl); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSignWith(t *testing.T) {
+	token, err := jwt.SignWith(tp, kms{rsaPrivateKey1}, jwt.KeyID("kms"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pl testPayload
+	hd, err := jwt.Verify(token, jwt.NewRS256(jwt.RSAPublicKey(rsaPublicKey1)), &pl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := (jwt.Header{Algorithm: "RS256", Type: "JWT", KeyID: "kms"}), hd; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := tp, pl; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestVerifyWith(t *testing.T) {
+	testCases := []struct {
+		signer   jwt.Algorithm
+		verifier jwt.Verifier
+		err      error
+	}{
+		{jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey1)), kms{rsaPrivateKey1}, nil},
+		{jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey2)), kms{rsaPrivateKey1}, jwt.ErrSignatureMismatch},
+		{jwt.NewRS384(jwt.RSAPrivateKey(rsaPrivateKey1)), kms{rsaPrivateKey1}, jwt.ErrAlgValidation},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.signer.Name(), func(t *testing.T) {
+			token, err := jwt.Sign(tp, tc.signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pl testPayload
+			_, err = jwt.VerifyWith(token, tc.verifier, &pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.VerifyWith err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
@@ -7,17 +7,28 @@ import (
 	"github.com/gbrlsnchs/jwt/v3/internal"
 )
 
+// ErrNumericDateRange is the error for a time-related claim after the year 9999,
+// which is rejected rather than risking an overflow. It wraps ErrMalformed.
+var ErrNumericDateRange = internal.WrapError("jwt: numeric date is out of range", ErrMalformed)
+
+// maxNumericDate is the Unix time of 9999-12-31T23:59:59Z.
+const maxNumericDate = 253402300799
+
 // Time is the allowed format for time, as per the RFC 7519.
+//
+// Times created by NumericDate and unmarshaled from claims are always in UTC, so they don't depend
+// on the local time zone. Validators compare instants with time.Time's methods, which ignore time zones,
+// so the current time passed to them can be in any time zone, with the same results.
 type Time struct {
 	time.Time
 }
 
-// NumericDate is a resolved Unix time.
+// NumericDate is a resolved Unix time, in UTC.
 func NumericDate(tt time.Time) *Time {
 	if tt.Before(internal.Epoch) {
 		tt = internal.Epoch
 	}
-	return &Time{time.Unix(tt.Unix(), 0)} // set time using Unix time
+	return &Time{time.Unix(tt.Unix(), 0).UTC()} // set time using Unix time
 }
 
 // MarshalJSON implements a marshaling function for time-related claims.
@@ -29,15 +40,34 @@ func (t Time) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON implements an unmarshaling function for time-related claims.
+// Fractional seconds are truncated, since some issuers set them.
+// Times before the Unix epoch are set to it, while times after the year 9999,
+// including ones that would overflow an int64, are rejected with ErrNumericDateRange.
 func (t *Time) UnmarshalJSON(b []byte) error {
 	var unix *int64
 	if err := json.Unmarshal(b, &unix); err != nil {
-		return err
+		var frac *float64
+		if json.Unmarshal(b, &frac) != nil {
+			return err
+		}
+		// frac is not nil, since null would have been unmarshaled into unix.
+		// It's checked before being converted, since converting an out of range float64 may wrap around.
+		if *frac >= maxNumericDate+1 {
+			return internal.Errorf("jwt: %s: %w", b, ErrNumericDateRange)
+		}
+		var i int64
+		if *frac > 0 {
+			i = int64(*frac)
+		}
+		unix = &i
 	}
 	if unix == nil {
 		return nil
 	}
-	tt := time.Unix(*unix, 0)
+	if *unix > maxNumericDate {
+		return internal.Errorf("jwt: %s: %w", b, ErrNumericDateRange)
+	}
+	tt := time.Unix(*unix, 0).UTC()
 	if tt.Before(internal.Epoch) {
 		tt = internal.Epoch
 	}
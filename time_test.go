@@ -1,6 +1,7 @@
 package jwt_test
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"testing"
 	"time"
@@ -71,3 +72,51 @@ func TestTimeUnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeUnmarshalJSONFractional(t *testing.T) {
+	testCases := []struct {
+		jstr string
+		want int64
+		err  bool
+	}{
+		{"1516239022.5", 1516239022, false},
+		{"1516239022.999", 1516239022, false},
+		{"1516239022.0", 1516239022, false},
+		{"1.516239022e9", 1516239022, false},
+		{"-1.5", 0, false},
+		{"-1e20", 0, false},
+		{"253402300799.5", 253402300799, false},
+		{"253402300800", 0, true},
+		{"1e20", 0, true},
+		{"99999999999999999999", 0, true},
+		{"9223372036854775807", 0, true},
+		{`"1516239022"`, 0, true},
+		{"true", 0, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.jstr, func(t *testing.T) {
+			var tt jwt.Time
+			err := tt.UnmarshalJSON([]byte(tc.jstr))
+			if want, got := tc.err, err != nil; got != want {
+				t.Fatalf("jwt.Time.Unmarshal err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if err == nil && tt.Unix() != tc.want {
+				t.Errorf("jwt.Time.Unmarshal mismatch (-want +got):\n%s", cmp.Diff(tc.want, tt.Unix()))
+			}
+		})
+	}
+}
+
+func TestTimeUnmarshalJSONOverflow(t *testing.T) {
+	for _, claim := range []string{"exp", "iat"} {
+		t.Run(claim, func(t *testing.T) {
+			payload := base64.RawURLEncoding.EncodeToString([]byte(`{"` + claim + `":99999999999999999999}`))
+			token := []byte("eyJhbGciOiJub25lIn0." + payload + ".")
+			var pl jwt.Payload
+			_, err := jwt.Verify(token, jwt.None(), &pl, jwt.ValidatePayload(&pl, jwt.TimeValidator(time.Now(), 0)))
+			if !internal.ErrorIs(err, jwt.ErrNumericDateRange) || !internal.ErrorIs(err, jwt.ErrMalformed) {
+				t.Errorf("jwt.Verify err = %v, want %v", err, jwt.ErrNumericDateRange)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package jwt
+
+import "time"
+
+// ValidatorOption is a functional option for Validators.
+type ValidatorOption func(*ValidatorFactory)
+
+// WithLeeway sets the clock skew allowed by all temporal validators created by a ValidatorFactory.
+// A negative leeway is treated as zero.
+func WithLeeway(d time.Duration) ValidatorOption {
+	return func(vf *ValidatorFactory) {
+		vf.leeway = nonNegative(d)
+	}
+}
+
+// ValidatorFactory creates temporal validators sharing the same settings,
+// so the "exp", "nbf" and "iat" claims can't accidentally be given different leeways.
+// Its validators compare claims with the time returned by Now when they run,
+// so they can be created once and reused, as well as combined with any other validator.
+type ValidatorFactory struct {
+	leeway time.Duration
+}
+
+// Validators creates a ValidatorFactory configured by opts:
+//
+//	vf := jwt.Validators(jwt.WithLeeway(30 * time.Second))
+//	vd := jwt.AndValidator(vf.Time(), jwt.AudienceValidator(aud))
+func Validators(opts ...ValidatorOption) *ValidatorFactory {
+	var vf ValidatorFactory
+	for _, opt := range opts {
+		opt(&vf)
+	}
+	return &vf
+}
+
+// ExpirationTime validates the "exp" claim, like ExpirationTimeValidatorNow.
+func (vf *ValidatorFactory) ExpirationTime() Validator {
+	return ExpirationTimeValidatorNow(vf.leeway)
+}
+
+// NotBefore validates the "nbf" claim, like NotBeforeValidatorNow.
+func (vf *ValidatorFactory) NotBefore() Validator {
+	return NotBeforeValidatorNow(vf.leeway)
+}
+
+// IssuedAt validates the "iat" claim, like IssuedAtValidatorNow.
+func (vf *ValidatorFactory) IssuedAt() Validator {
+	return IssuedAtValidatorNow(vf.leeway)
+}
+
+// Time validates the "exp", "nbf" and "iat" claims at once, like TimeValidator.
+func (vf *ValidatorFactory) Time() Validator {
+	return func(pl *Payload) error {
+		return TimeValidator(Now(), vf.leeway)(pl)
+	}
+}
@@ -1,6 +1,10 @@
 package jwt
 
 import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gbrlsnchs/jwt/v3/internal"
@@ -9,6 +13,14 @@ import (
 var (
 	// ErrAudValidation is the error for an invalid "aud" claim.
 	ErrAudValidation = internal.NewError("jwt: aud claim is invalid")
+	// ErrAudMissing is the error for a missing "aud" claim, usually a client misconfiguration.
+	// It wraps ErrAudValidation.
+	ErrAudMissing = internal.WrapError("jwt: aud claim is missing", ErrAudValidation)
+	// ErrAudMismatch is the error for an "aud" claim with none of the expected audiences,
+	// which may be an attempt to use a token meant for another audience. It wraps ErrAudValidation.
+	ErrAudMismatch = internal.WrapError("jwt: aud claim mismatches", ErrAudValidation)
+	// ErrClaimValidation is the error for an invalid private claim.
+	ErrClaimValidation = internal.NewError("jwt: private claim is invalid")
 	// ErrExpValidation is the error for an invalid "exp" claim.
 	ErrExpValidation = internal.NewError("jwt: exp claim is invalid")
 	// ErrIatValidation is the error for an invalid "iat" claim.
@@ -17,55 +29,495 @@ var (
 	ErrIssValidation = internal.NewError("jwt: iss claim is invalid")
 	// ErrJtiValidation is the error for an invalid "jti" claim.
 	ErrJtiValidation = internal.NewError("jwt: jti claim is invalid")
+	// ErrLifetimeValidation is the error for when a JWT's lifetime, from "iat" to "exp", is too long.
+	ErrLifetimeValidation = internal.NewError("jwt: token lifetime is invalid")
+	// ErrMaxAgeValidation is the error for when a JWT has been issued too long ago.
+	ErrMaxAgeValidation = internal.NewError("jwt: token is too old")
 	// ErrNbfValidation is the error for an invalid "nbf" claim.
 	ErrNbfValidation = internal.NewError("jwt: nbf claim is invalid")
 	// ErrSubValidation is the error for an invalid "sub" claim.
 	ErrSubValidation = internal.NewError("jwt: sub claim is invalid")
 )
 
+// Now returns the current time for validators that don't take it as an argument,
+// such as ExpirationTimeValidatorNow. It can be replaced, for example, in tests.
+var Now = time.Now
+
 // Validator is a function that validates a Payload pointer.
 type Validator func(*Payload) error
 
+// AndValidator combines validators so that all of them must pass.
+// Validators are run in the order informed and the first error is returned.
+func AndValidator(vds ...Validator) Validator {
+	return func(pl *Payload) error {
+		for _, vd := range vds {
+			if err := vd(pl); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ConditionalValidator runs then only when the Payload satisfies when, and passes otherwise.
+// It's useful for policies relating claims, for example, requiring an audience for a given issuer:
+//
+//	jwt.ConditionalValidator(func(pl *jwt.Payload) bool {
+//		return pl.Issuer == "https://accounts.example.com"
+//	}, jwt.AudienceValidator(jwt.Audience{"client-id"}))
+func ConditionalValidator(when func(*Payload) bool, then Validator) Validator {
+	return func(pl *Payload) error {
+		if !when(pl) {
+			return nil
+		}
+		return then(pl)
+	}
+}
+
+// ValidateAll combines validators so that all of them must pass, like AndValidator,
+// but runs every validator and returns all errors as ValidationErrors.
+// It's slower than AndValidator, but useful for reporting every problem at once.
+func ValidateAll(vds ...Validator) Validator {
+	return func(pl *Payload) error {
+		var errs ValidationErrors
+		for _, vd := range vds {
+			if err := vd(pl); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		return errs
+	}
+}
+
+// ValidationErrors holds the errors returned by validators combined with ValidateAll.
+// Checking it with errors.Is or errors.As checks each one of the errors.
+type ValidationErrors []error
+
+// Error joins the messages of all errors.
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns all errors.
+func (errs ValidationErrors) Unwrap() []error {
+	return errs
+}
+
+// Is reports whether any of the errors matches target.
+func (errs ValidationErrors) Is(target error) bool {
+	for _, err := range errs {
+		if internal.ErrorIs(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches target.
+func (errs ValidationErrors) As(target interface{}) bool {
+	for _, err := range errs {
+		if internal.ErrorAs(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatorError holds the error returned by a validator along with its position
+// in the list passed to ValidatePayload. It's only returned when ReportValidatorIndex is set.
+type ValidatorError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the validator's error message prefixed with its index.
+func (e *ValidatorError) Error() string {
+	return "jwt: validator " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the validator's error.
+func (e *ValidatorError) Unwrap() error {
+	return e.Err
+}
+
+// OrValidator combines validators so that at least one of them must pass.
+// Validators are run in the order informed until one of them passes.
+// If none passes, the error from the last one is returned.
+// Note that an empty list of validators always passes.
+func OrValidator(vds ...Validator) Validator {
+	return func(pl *Payload) error {
+		var err error
+		for _, vd := range vds {
+			if err = vd(pl); err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+}
+
 // AudienceValidator validates the "aud" claim.
 // It checks if at least one of the audiences in the JWT's payload is listed in aud.
 func AudienceValidator(aud Audience) Validator {
+	return AudienceValidatorFunc(aud, func(clientAud, serverAud string) bool {
+		return clientAud == serverAud
+	})
+}
+
+// AudienceValidatorString is like AudienceValidator, but for servers with a single audience,
+// such as their own client ID. It checks if expected is one of the audiences in the JWT's payload.
+func AudienceValidatorString(expected string) Validator {
+	return AudienceValidator(Audience{expected})
+}
+
+// AudienceValidatorFunc validates the "aud" claim using a custom matching function.
+// It checks if at least one of the audiences in the JWT's payload matches one listed in aud.
+//
+// Functions such as strings.EqualFold and WildcardAudienceMatch can be used as match.
+//
+// The returned error wraps ErrAudMissing if the JWT has no audiences and ErrAudMismatch otherwise.
+func AudienceValidatorFunc(aud Audience, match func(clientAud, serverAud string) bool) Validator {
 	return func(pl *Payload) error {
+		if len(pl.Audience) == 0 {
+			return internal.Errorf("jwt: want one of %q: %w", []string(aud), ErrAudMissing)
+		}
 		for _, serverAud := range aud {
 			for _, clientAud := range pl.Audience {
-				if clientAud == serverAud {
+				if match(clientAud, serverAud) {
 					return nil
 				}
 			}
 		}
-		return ErrAudValidation
+		return internal.Errorf("jwt: got %q, want one of %q: %w", []string(pl.Audience), []string(aud), ErrAudMismatch)
+	}
+}
+
+// RequireAudienceValidator validates that the "aud" claim is present, regardless of its value,
+// so all JWTs can be required to have an audience before matching it with AudienceValidator.
+// A missing "aud" claim and an empty array are both rejected with an error wrapping ErrAudMissing.
+func RequireAudienceValidator() Validator {
+	return func(pl *Payload) error {
+		if len(pl.Audience) == 0 {
+			return internal.Errorf("jwt: \"aud\" is missing: %w", ErrAudMissing)
+		}
+		return nil
+	}
+}
+
+// WildcardAudienceMatch reports whether clientAud matches serverAud, ignoring case.
+// A single "*" in serverAud matches one non-empty DNS label in clientAud,
+// so that "https://*.example.com" matches "https://api.example.com"
+// but neither "https://example.com" nor "https://a.b.example.com".
+func WildcardAudienceMatch(clientAud, serverAud string) bool {
+	i := strings.IndexByte(serverAud, '*')
+	if i < 0 {
+		return strings.EqualFold(clientAud, serverAud)
+	}
+	prefix, suffix := serverAud[:i], serverAud[i+1:]
+	if strings.IndexByte(suffix, '*') >= 0 || len(clientAud) <= len(prefix)+len(suffix) {
+		return false
+	}
+	if !strings.EqualFold(clientAud[:len(prefix)], prefix) ||
+		!strings.EqualFold(clientAud[len(clientAud)-len(suffix):], suffix) {
+		return false
+	}
+	label := clientAud[len(prefix) : len(clientAud)-len(suffix)]
+	return !strings.ContainsAny(label, "./:")
+}
+
+// AudienceValidatorAll validates the "aud" claim.
+// It checks if all audiences listed in aud are present in the JWT's payload.
+// An empty aud is considered a misconfiguration and never passes.
+//
+// Like AudienceValidator, the returned error wraps ErrAudMissing or ErrAudMismatch.
+func AudienceValidatorAll(aud Audience) Validator {
+	return func(pl *Payload) error {
+		if len(aud) == 0 {
+			return internal.Errorf("jwt: no audiences required: %w", ErrAudValidation)
+		}
+		if len(pl.Audience) == 0 {
+			return internal.Errorf("jwt: want all of %q: %w", []string(aud), ErrAudMissing)
+		}
+		for _, serverAud := range aud {
+			found := false
+			for _, clientAud := range pl.Audience {
+				if clientAud == serverAud {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return internal.Errorf("jwt: got %q, missing %q: %w", []string(pl.Audience), serverAud, ErrAudMismatch)
+			}
+		}
+		return nil
+	}
+}
+
+// BlacklistValidator validates the "jti" claim against a revocation list.
+// It rejects JWTs whose ID is reported as revoked by isRevoked, as well as JWTs without an ID,
+// since their revocation can't be checked. Errors returned by isRevoked are returned as is,
+// so lookups can be backed by external storage.
+func BlacklistValidator(isRevoked func(jti string) (bool, error)) Validator {
+	return func(pl *Payload) error {
+		if pl.JWTID == "" {
+			return internal.Errorf("jwt: jti is missing: %w", ErrJtiValidation)
+		}
+		revoked, err := isRevoked(pl.JWTID)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return internal.Errorf("jwt: %q is revoked: %w", pl.JWTID, ErrJtiValidation)
+		}
+		return nil
+	}
+}
+
+// ReplayValidator validates that the "jti" claim of one-time-use JWTs, such as password reset links,
+// is seen at most once. The seen function must atomically record jti and report whether it was already used,
+// as done by Redis' SETNX, and should keep it at least until the JWT expires.
+// Like BlacklistValidator, it rejects JWTs without an ID and returns errors from seen as is.
+//
+// Since it records jti as a side effect, it should be the last validator to run,
+// so JWTs rejected by other validators aren't marked as used.
+func ReplayValidator(seen func(jti string) (alreadyUsed bool, err error)) Validator {
+	return func(pl *Payload) error {
+		if pl.JWTID == "" {
+			return internal.Errorf("jwt: jti is missing: %w", ErrJtiValidation)
+		}
+		used, err := seen(pl.JWTID)
+		if err != nil {
+			return err
+		}
+		if used {
+			return internal.Errorf("jwt: %q has already been used: %w", pl.JWTID, ErrJtiValidation)
+		}
+		return nil
+	}
+}
+
+// ClaimValidator validates a private claim.
+// It checks if the claim called name is equal to expected once both are
+// represented as JSON, so numbers match regardless of their Go type.
+//
+// Since it reads private claims from PrivateClaims, it only works when
+// verifying into a Payload rather than a struct that embeds it.
+func ClaimValidator(name string, expected interface{}) Validator {
+	return func(pl *Payload) error {
+		raw, ok := pl.PrivateClaims[name]
+		if !ok {
+			return internal.Errorf("jwt: %q is missing: %w", name, ErrClaimValidation)
+		}
+		eb, err := JSONMarshal(expected)
+		if err != nil {
+			return err
+		}
+		var got, want interface{}
+		if err = JSONUnmarshal(raw, &got); err != nil {
+			return err
+		}
+		if err = JSONUnmarshal(eb, &want); err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(got, want) {
+			return internal.Errorf("jwt: %q: got %s, want %s: %w", name, raw, eb, ErrClaimValidation)
+		}
+		return nil
+	}
+}
+
+// StringClaimValidator validates a private claim holding a string.
+// It checks if the claim called name exactly matches one of the strings listed in values.
+//
+// Like ClaimValidator, it only works when verifying into a Payload.
+func StringClaimValidator(name string, values ...string) Validator {
+	return func(pl *Payload) error {
+		var v string
+		if err := pl.Get(name, &v); err != nil {
+			return internal.Errorf("jwt: %q: %v: %w", name, err, ErrClaimValidation)
+		}
+		for _, value := range values {
+			if v == value {
+				return nil
+			}
+		}
+		return internal.Errorf("jwt: %q: got %q, want one of %q: %w", name, v, values, ErrClaimValidation)
+	}
+}
+
+// Op is a comparison operator for NumericClaimValidator.
+type Op int
+
+// Comparison operators, testing the claim against the value passed to NumericClaimValidator.
+const (
+	OpEQ Op = iota
+	OpNE
+	OpGT
+	OpGTE
+	OpLT
+	OpLTE
+)
+
+var opSymbols = [...]string{OpEQ: "==", OpNE: "!=", OpGT: ">", OpGTE: ">=", OpLT: "<", OpLTE: "<="}
+
+func (op Op) String() string {
+	if op < 0 || int(op) >= len(opSymbols) {
+		return "Op(" + strconv.Itoa(int(op)) + ")"
+	}
+	return opSymbols[op]
+}
+
+func (op Op) compare(x, y float64) bool {
+	switch op {
+	case OpNE:
+		return x != y
+	case OpGT:
+		return x > y
+	case OpGTE:
+		return x >= y
+	case OpLT:
+		return x < y
+	case OpLTE:
+		return x <= y
+	}
+	return x == y
+}
+
+// NumericClaimValidator validates a private claim holding a number.
+// It checks if the claim called name compares to value as per op, for example, "level" >= 3
+// with OpGTE. Since JSON numbers are decoded as float64, so are integer claims.
+// It panics if op is not one of the operators above.
+//
+// Like ClaimValidator, it only works when verifying into a Payload.
+func NumericClaimValidator(name string, op Op, value float64) Validator {
+	if op < 0 || int(op) >= len(opSymbols) {
+		panic(internal.Errorf("jwt: invalid operator %v: %w", op, ErrClaimValidation))
+	}
+	return func(pl *Payload) error {
+		var v float64
+		if err := pl.Get(name, &v); err != nil {
+			return internal.Errorf("jwt: %q: %v: %w", name, err, ErrClaimValidation)
+		}
+		if !op.compare(v, value) {
+			return internal.Errorf("jwt: %q: got %v, want %v %v: %w", name, v, op, value, ErrClaimValidation)
+		}
+		return nil
+	}
+}
+
+// BoolClaimValidator validates a private claim holding a boolean, such as "mfa".
+// It checks if the claim called name is expected.
+//
+// Like ClaimValidator, it only works when verifying into a Payload.
+func BoolClaimValidator(name string, expected bool) Validator {
+	return func(pl *Payload) error {
+		var v bool
+		if err := pl.Get(name, &v); err != nil {
+			return internal.Errorf("jwt: %q: %v: %w", name, err, ErrClaimValidation)
+		}
+		if v != expected {
+			return internal.Errorf("jwt: %q: got %t, want %t: %w", name, v, expected, ErrClaimValidation)
+		}
+		return nil
 	}
 }
 
 // ExpirationTimeValidator validates the "exp" claim.
+// A JWT without an "exp" claim never passes, so tokens that don't expire are always rejected.
+// An "exp" claim set to zero is the Unix epoch, so it always fails as well.
 func ExpirationTimeValidator(now time.Time) Validator {
+	return ExpirationTimeValidatorWithLeeway(now, 0)
+}
+
+// ExpirationTimeValidatorWithLeeway validates the "exp" claim allowing some clock skew.
+// The JWT is considered expired only when now is after "exp" plus leeway.
+// A negative leeway is treated as zero. Like ExpirationTimeValidator, it requires the "exp" claim.
+func ExpirationTimeValidatorWithLeeway(now time.Time, leeway time.Duration) Validator {
+	leeway = nonNegative(leeway)
 	return func(pl *Payload) error {
-		if pl.ExpirationTime == nil || NumericDate(now).After(pl.ExpirationTime.Time) {
-			return ErrExpValidation
+		if pl.ExpirationTime == nil {
+			return internal.Errorf("jwt: exp is missing: %w", ErrExpValidation)
+		}
+		if now := NumericDate(now); now.After(pl.ExpirationTime.Add(leeway)) {
+			return internal.Errorf("jwt: expired at %d, now is %d: %w", pl.ExpirationTime.Unix(), now.Unix(), ErrExpValidation)
 		}
 		return nil
 	}
 }
 
+// ExpirationTimeValidatorNow is like ExpirationTimeValidatorWithLeeway, but compares "exp"
+// with the time returned by Now at the moment of validation.
+func ExpirationTimeValidatorNow(leeway time.Duration) Validator {
+	return func(pl *Payload) error {
+		return ExpirationTimeValidatorWithLeeway(Now(), leeway)(pl)
+	}
+}
+
 // IssuedAtValidator validates the "iat" claim.
 func IssuedAtValidator(now time.Time) Validator {
+	return IssuedAtValidatorWithLeeway(now, 0)
+}
+
+// IssuedAtValidatorWithLeeway validates the "iat" claim allowing some clock skew.
+// The JWT is considered issued in the future only when now is before "iat" minus leeway.
+// A negative leeway is treated as zero.
+func IssuedAtValidatorWithLeeway(now time.Time, leeway time.Duration) Validator {
+	leeway = nonNegative(leeway)
 	return func(pl *Payload) error {
-		if pl.IssuedAt != nil && NumericDate(now).Before(pl.IssuedAt.Time) {
-			return ErrIatValidation
+		if pl.IssuedAt == nil {
+			return nil
+		}
+		if now := NumericDate(now); now.Before(pl.IssuedAt.Add(-leeway)) {
+			return internal.Errorf("jwt: issued at %d, now is %d: %w", pl.IssuedAt.Unix(), now.Unix(), ErrIatValidation)
 		}
 		return nil
 	}
 }
 
+// IssuedAtValidatorNow is like IssuedAtValidatorWithLeeway, but compares "iat"
+// with the time returned by Now at the moment of validation.
+func IssuedAtValidatorNow(leeway time.Duration) Validator {
+	return func(pl *Payload) error {
+		return IssuedAtValidatorWithLeeway(Now(), leeway)(pl)
+	}
+}
+
 // IssuerValidator validates the "iss" claim.
 func IssuerValidator(iss string) Validator {
+	return IssuersValidator(iss)
+}
+
+// IssuersValidator validates the "iss" claim.
+// It checks if the JWT's issuer exactly matches one of the issuers listed in iss.
+func IssuersValidator(iss ...string) Validator {
+	return func(pl *Payload) error {
+		for _, serverIss := range iss {
+			if pl.Issuer == serverIss {
+				return nil
+			}
+		}
+		return internal.Errorf("jwt: got %q, want one of %q: %w", pl.Issuer, iss, ErrIssValidation)
+	}
+}
+
+// IssuerFuncValidator validates the "iss" claim using a function that reports whether an issuer
+// is trusted, so the set of issuers can change at runtime, for example, by looking it up in a database.
+// A JWT without an issuer never passes, and allowed isn't called for it.
+func IssuerFuncValidator(allowed func(iss string) bool) Validator {
 	return func(pl *Payload) error {
-		if pl.Issuer != iss {
-			return ErrIssValidation
+		if pl.Issuer == "" {
+			return internal.Errorf("jwt: missing issuer: %w", ErrIssValidation)
+		}
+		if !allowed(pl.Issuer) {
+			return internal.Errorf("jwt: %q is not allowed: %w", pl.Issuer, ErrIssValidation)
 		}
 		return nil
 	}
@@ -75,7 +527,62 @@ func IssuerValidator(iss string) Validator {
 func IDValidator(jti string) Validator {
 	return func(pl *Payload) error {
 		if pl.JWTID != jti {
-			return ErrJtiValidation
+			return internal.Errorf("jwt: got %q, want %q: %w", pl.JWTID, jti, ErrJtiValidation)
+		}
+		return nil
+	}
+}
+
+// IssuedAtStrictValidator validates that the "iat" claim is present, not in the future and recent.
+// It returns an error wrapping:
+//   - ErrIatValidation when "iat" is missing or when now is before "iat" minus leeway;
+//   - ErrMaxAgeValidation when more than maxAge has passed since "iat".
+//
+// A negative leeway is treated as zero. Leeway doesn't extend maxAge.
+func IssuedAtStrictValidator(now time.Time, leeway, maxAge time.Duration) Validator {
+	iat := IssuedAtValidatorWithLeeway(now, leeway)
+	age := MaxAgeValidator(now, maxAge)
+	return func(pl *Payload) error {
+		if err := iat(pl); err != nil {
+			return err
+		}
+		return age(pl)
+	}
+}
+
+// MaxAgeValidator validates the "iat" claim against a maximum age.
+// It checks that no more than maxAge has passed since the JWT was issued.
+// Since freshness can't be proved without it, a missing "iat" claim never passes.
+func MaxAgeValidator(now time.Time, maxAge time.Duration) Validator {
+	return func(pl *Payload) error {
+		if pl.IssuedAt == nil {
+			return internal.Errorf("jwt: iat is missing: %w", ErrIatValidation)
+		}
+		if age := NumericDate(now).Sub(pl.IssuedAt.Time); age > maxAge {
+			return internal.Errorf("jwt: issued %v ago, max age is %v: %w", age, maxAge, ErrMaxAgeValidation)
+		}
+		return nil
+	}
+}
+
+// MaxLifetimeValidator validates that the time between the "iat" and "exp" claims is positive
+// and not longer than max, whether or not the JWT has already expired.
+// It catches issuers misconfigured to mint long-lived tokens.
+// Since lifetime can't be computed without them, missing "iat" or "exp" claims never pass.
+func MaxLifetimeValidator(max time.Duration) Validator {
+	return func(pl *Payload) error {
+		if pl.IssuedAt == nil {
+			return internal.Errorf("jwt: iat is missing: %w", ErrIatValidation)
+		}
+		if pl.ExpirationTime == nil {
+			return internal.Errorf("jwt: exp is missing: %w", ErrExpValidation)
+		}
+		lifetime := pl.ExpirationTime.Sub(pl.IssuedAt.Time)
+		if lifetime <= 0 {
+			return internal.Errorf("jwt: exp is not after iat: %w", ErrLifetimeValidation)
+		}
+		if lifetime > max {
+			return internal.Errorf("jwt: lifetime is %v, max is %v: %w", lifetime, max, ErrLifetimeValidation)
 		}
 		return nil
 	}
@@ -83,9 +590,41 @@ func IDValidator(jti string) Validator {
 
 // NotBeforeValidator validates the "nbf" claim.
 func NotBeforeValidator(now time.Time) Validator {
+	return NotBeforeValidatorWithLeeway(now, 0)
+}
+
+// NotBeforeValidatorWithLeeway validates the "nbf" claim allowing some clock skew.
+// The JWT is considered not valid yet only when now is before "nbf" minus leeway.
+// A negative leeway is treated as zero.
+func NotBeforeValidatorWithLeeway(now time.Time, leeway time.Duration) Validator {
+	return notBeforeValidator(now, leeway, false)
+}
+
+// NotBeforeValidatorNow is like NotBeforeValidatorWithLeeway, but compares "nbf"
+// with the time returned by Now at the moment of validation.
+func NotBeforeValidatorNow(leeway time.Duration) Validator {
+	return func(pl *Payload) error {
+		return NotBeforeValidatorWithLeeway(Now(), leeway)(pl)
+	}
+}
+
+// RequiredNotBeforeValidator validates the "nbf" claim like NotBeforeValidatorWithLeeway,
+// but also requires the claim to be set to a date after the Unix epoch.
+func RequiredNotBeforeValidator(now time.Time, leeway time.Duration) Validator {
+	return notBeforeValidator(now, leeway, true)
+}
+
+func notBeforeValidator(now time.Time, leeway time.Duration, required bool) Validator {
+	leeway = nonNegative(leeway)
 	return func(pl *Payload) error {
-		if pl.NotBefore != nil && NumericDate(now).Before(pl.NotBefore.Time) {
-			return ErrNbfValidation
+		if pl.NotBefore == nil || pl.NotBefore.Unix() == 0 {
+			if required {
+				return internal.Errorf("jwt: nbf is missing: %w", ErrNbfValidation)
+			}
+			return nil
+		}
+		if now := NumericDate(now); now.Before(pl.NotBefore.Add(-leeway)) {
+			return internal.Errorf("jwt: not valid before %d, now is %d: %w", pl.NotBefore.Unix(), now.Unix(), ErrNbfValidation)
 		}
 		return nil
 	}
@@ -95,8 +634,51 @@ func NotBeforeValidator(now time.Time) Validator {
 func SubjectValidator(sub string) Validator {
 	return func(pl *Payload) error {
 		if pl.Subject != sub {
-			return ErrSubValidation
+			return internal.Errorf("jwt: got %q, want %q: %w", pl.Subject, sub, ErrSubValidation)
+		}
+		return nil
+	}
+}
+
+// SubjectPrefixValidator validates the "sub" claim.
+// It checks if the JWT's subject starts with prefix, for example, a tenant's namespace.
+func SubjectPrefixValidator(prefix string) Validator {
+	return func(pl *Payload) error {
+		if !strings.HasPrefix(pl.Subject, prefix) {
+			return internal.Errorf("jwt: got %q, want prefix %q: %w", pl.Subject, prefix, ErrSubValidation)
 		}
 		return nil
 	}
 }
+
+// SubjectRegexValidator validates the "sub" claim.
+// It checks if the JWT's subject matches re, which should be anchored for matching the whole subject.
+func SubjectRegexValidator(re *regexp.Regexp) Validator {
+	return func(pl *Payload) error {
+		if !re.MatchString(pl.Subject) {
+			return internal.Errorf("jwt: got %q, want match for %q: %w", pl.Subject, re, ErrSubValidation)
+		}
+		return nil
+	}
+}
+
+// TimeValidator validates the "exp", "nbf" and "iat" claims at once, in that order, allowing the same
+// clock skew for all of them. It's the recommended way of validating temporal claims.
+//
+// It stops at the first invalid claim and returns its error, which wraps ErrExpValidation,
+// ErrNbfValidation or ErrIatValidation. Like the validators for each claim, it requires "exp",
+// but not "nbf" or "iat".
+func TimeValidator(now time.Time, leeway time.Duration) Validator {
+	return AndValidator(
+		ExpirationTimeValidatorWithLeeway(now, leeway),
+		NotBeforeValidatorWithLeeway(now, leeway),
+		IssuedAtValidatorWithLeeway(now, leeway),
+	)
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -1,6 +1,10 @@
 package jwt_test
 
 import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,6 +13,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 )
 
+var subRegexp = regexp.MustCompile(`^tenant:[a-z]+:user:[0-9]+$`)
+
 func TestValidators(t *testing.T) {
 	now := time.Now()
 	iat := jwt.NumericDate(now)
@@ -26,18 +32,42 @@ func TestValidators(t *testing.T) {
 	}{
 		{"iss", &jwt.Payload{Issuer: iss}, jwt.IssuerValidator("iss"), nil},
 		{"iss", &jwt.Payload{Issuer: iss}, jwt.IssuerValidator("not_iss"), jwt.ErrIssValidation},
+		{"iss", &jwt.Payload{Issuer: iss}, jwt.IssuersValidator("foo", "iss"), nil},
+		{"iss", &jwt.Payload{Issuer: iss}, jwt.IssuersValidator("foo", "is", "iss2"), jwt.ErrIssValidation},
+		{"iss", &jwt.Payload{Issuer: iss}, jwt.IssuersValidator(), jwt.ErrIssValidation},
+		{"iss", &jwt.Payload{}, jwt.IssuersValidator("iss"), jwt.ErrIssValidation},
+		{"iss", &jwt.Payload{Issuer: iss}, jwt.IssuerFuncValidator(func(s string) bool { return s == iss }), nil},
+		{"iss", &jwt.Payload{Issuer: "foo"}, jwt.IssuerFuncValidator(func(s string) bool { return s == iss }), jwt.ErrIssValidation},
+		{"iss", &jwt.Payload{}, jwt.IssuerFuncValidator(func(string) bool { return true }), jwt.ErrIssValidation},
 		{"sub", &jwt.Payload{Subject: sub}, jwt.SubjectValidator("sub"), nil},
 		{"sub", &jwt.Payload{Subject: sub}, jwt.SubjectValidator("not_sub"), jwt.ErrSubValidation},
+		{"sub", &jwt.Payload{Subject: "tenant:acme:user:123"}, jwt.SubjectPrefixValidator("tenant:acme:"), nil},
+		{"sub", &jwt.Payload{Subject: "tenant:globex:user:123"}, jwt.SubjectPrefixValidator("tenant:acme:"), jwt.ErrSubValidation},
+		{"sub", &jwt.Payload{}, jwt.SubjectPrefixValidator(""), nil},
+		{"sub", &jwt.Payload{Subject: "tenant:acme:user:123"}, jwt.SubjectRegexValidator(subRegexp), nil},
+		{"sub", &jwt.Payload{Subject: "tenant:acme:user:abc"}, jwt.SubjectRegexValidator(subRegexp), jwt.ErrSubValidation},
+		{"sub", &jwt.Payload{Subject: "x:tenant:acme:user:123"}, jwt.SubjectRegexValidator(subRegexp), jwt.ErrSubValidation},
 		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidator(jwt.Audience{"aud"}), nil},
 		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidator(jwt.Audience{"foo", "aud1"}), nil},
 		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidator(jwt.Audience{"bar", "aud2"}), nil},
 		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidator(jwt.Audience{"baz", "aud3"}), nil},
 		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidator(jwt.Audience{"qux", "aud4"}), jwt.ErrAudValidation},
+		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidatorString("aud2"), nil},
+		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidatorString("aud4"), jwt.ErrAudMismatch},
+		{"aud", &jwt.Payload{}, jwt.AudienceValidatorString("aud"), jwt.ErrAudMissing},
 		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidator(jwt.Audience{"not_aud"}), jwt.ErrAudValidation},
+		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidatorAll(jwt.Audience{"aud", "aud3"}), nil},
+		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidatorAll(jwt.Audience{"aud1", "aud1"}), nil},
+		{"aud", &jwt.Payload{Audience: append(aud, "aud")}, jwt.AudienceValidatorAll(jwt.Audience{"aud"}), nil},
+		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidatorAll(jwt.Audience{"aud", "aud4"}), jwt.ErrAudValidation},
+		{"aud", &jwt.Payload{}, jwt.AudienceValidatorAll(jwt.Audience{"aud"}), jwt.ErrAudValidation},
+		{"aud", &jwt.Payload{Audience: aud}, jwt.AudienceValidatorAll(nil), jwt.ErrAudValidation},
 		{"exp", &jwt.Payload{ExpirationTime: exp}, jwt.ExpirationTimeValidator(now), nil},
 		{"exp", &jwt.Payload{ExpirationTime: exp}, jwt.ExpirationTimeValidator(time.Unix(now.Unix()-int64(24*time.Hour), 0)), nil},
 		{"exp", &jwt.Payload{ExpirationTime: exp}, jwt.ExpirationTimeValidator(time.Unix(now.Unix()+int64(24*time.Hour), 0)), jwt.ErrExpValidation},
 		{"exp", &jwt.Payload{}, jwt.ExpirationTimeValidator(time.Now()), jwt.ErrExpValidation},
+		{"exp", &jwt.Payload{ExpirationTime: jwt.NumericDate(time.Unix(0, 0))}, jwt.ExpirationTimeValidator(time.Now()), jwt.ErrExpValidation},
+		{"exp", &jwt.Payload{ExpirationTime: jwt.NumericDate(time.Unix(0, 0))}, jwt.ExpirationTimeValidatorWithLeeway(time.Now(), time.Hour), jwt.ErrExpValidation},
 		{"nbf", &jwt.Payload{NotBefore: nbf}, jwt.NotBeforeValidator(now), jwt.ErrNbfValidation},
 		{"nbf", &jwt.Payload{NotBefore: nbf}, jwt.NotBeforeValidator(time.Unix(now.Unix()+int64(15*time.Second), 0)), nil},
 		{"nbf", &jwt.Payload{NotBefore: nbf}, jwt.NotBeforeValidator(time.Unix(now.Unix()-int64(15*time.Second), 0)), jwt.ErrNbfValidation},
@@ -46,6 +76,42 @@ func TestValidators(t *testing.T) {
 		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.IssuedAtValidator(time.Unix(now.Unix()+1, 0)), nil},
 		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.IssuedAtValidator(time.Unix(now.Unix()-1, 0)), jwt.ErrIatValidation},
 		{"iat", &jwt.Payload{}, jwt.IssuedAtValidator(time.Now()), nil},
+		{"exp", &jwt.Payload{ExpirationTime: exp}, jwt.ExpirationTimeValidatorWithLeeway(now.Add(24*time.Hour+5*time.Second), 10*time.Second), nil},
+		{"exp", &jwt.Payload{ExpirationTime: exp}, jwt.ExpirationTimeValidatorWithLeeway(now.Add(24*time.Hour+15*time.Second), 10*time.Second), jwt.ErrExpValidation},
+		{"exp", &jwt.Payload{ExpirationTime: exp}, jwt.ExpirationTimeValidatorWithLeeway(now.Add(24*time.Hour+5*time.Second), -10*time.Second), jwt.ErrExpValidation},
+		{"exp", &jwt.Payload{}, jwt.ExpirationTimeValidatorWithLeeway(now, time.Hour), jwt.ErrExpValidation},
+		{"nbf", &jwt.Payload{NotBefore: nbf}, jwt.NotBeforeValidatorWithLeeway(now, 20*time.Second), nil},
+		{"nbf", &jwt.Payload{NotBefore: nbf}, jwt.NotBeforeValidatorWithLeeway(now, 10*time.Second), jwt.ErrNbfValidation},
+		{"nbf", &jwt.Payload{NotBefore: nbf}, jwt.NotBeforeValidatorWithLeeway(now, -20*time.Second), jwt.ErrNbfValidation},
+		{"nbf", &jwt.Payload{NotBefore: nbf}, jwt.RequiredNotBeforeValidator(now, 20*time.Second), nil},
+		{"nbf", &jwt.Payload{NotBefore: nbf}, jwt.RequiredNotBeforeValidator(now, 10*time.Second), jwt.ErrNbfValidation},
+		{"nbf", &jwt.Payload{}, jwt.RequiredNotBeforeValidator(now, time.Hour), jwt.ErrNbfValidation},
+		{"nbf", &jwt.Payload{NotBefore: jwt.NumericDate(time.Unix(0, 0))}, jwt.RequiredNotBeforeValidator(now, 0), jwt.ErrNbfValidation},
+		{"nbf", &jwt.Payload{NotBefore: jwt.NumericDate(time.Unix(0, 0))}, jwt.NotBeforeValidatorWithLeeway(now, 0), nil},
+		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.IssuedAtValidatorWithLeeway(now.Add(-5*time.Second), 10*time.Second), nil},
+		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.IssuedAtValidatorWithLeeway(now.Add(-15*time.Second), 10*time.Second), jwt.ErrIatValidation},
+		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.MaxAgeValidator(now.Add(time.Hour), time.Hour), nil},
+		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.MaxAgeValidator(now.Add(time.Hour+time.Second), time.Hour), jwt.ErrMaxAgeValidation},
+		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.MaxAgeValidator(now, 0), nil},
+		{"iat", &jwt.Payload{}, jwt.MaxAgeValidator(now, time.Hour), jwt.ErrIatValidation},
+		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.IssuedAtStrictValidator(now.Add(time.Hour), 0, time.Hour), nil},
+		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.IssuedAtStrictValidator(now.Add(-5*time.Second), 10*time.Second, time.Hour), nil},
+		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.IssuedAtStrictValidator(now.Add(-15*time.Second), 10*time.Second, time.Hour), jwt.ErrIatValidation},
+		{"iat", &jwt.Payload{IssuedAt: iat}, jwt.IssuedAtStrictValidator(now.Add(time.Hour+time.Second), time.Minute, time.Hour), jwt.ErrMaxAgeValidation},
+		{"iat", &jwt.Payload{}, jwt.IssuedAtStrictValidator(now, time.Minute, time.Hour), jwt.ErrIatValidation},
+		{"lifetime", &jwt.Payload{IssuedAt: iat, ExpirationTime: exp}, jwt.MaxLifetimeValidator(24 * time.Hour), nil},
+		{"lifetime", &jwt.Payload{IssuedAt: iat, ExpirationTime: exp}, jwt.MaxLifetimeValidator(time.Hour), jwt.ErrLifetimeValidation},
+		{"lifetime", &jwt.Payload{IssuedAt: exp, ExpirationTime: iat}, jwt.MaxLifetimeValidator(24 * time.Hour), jwt.ErrLifetimeValidation},
+		{"lifetime", &jwt.Payload{IssuedAt: iat, ExpirationTime: iat}, jwt.MaxLifetimeValidator(24 * time.Hour), jwt.ErrLifetimeValidation},
+		{"lifetime", &jwt.Payload{ExpirationTime: exp}, jwt.MaxLifetimeValidator(24 * time.Hour), jwt.ErrIatValidation},
+		{"lifetime", &jwt.Payload{IssuedAt: iat}, jwt.MaxLifetimeValidator(24 * time.Hour), jwt.ErrExpValidation},
+		{"time", &jwt.Payload{ExpirationTime: exp, NotBefore: nbf, IssuedAt: iat}, jwt.TimeValidator(now.Add(time.Minute), 0), nil},
+		{"time", &jwt.Payload{ExpirationTime: exp}, jwt.TimeValidator(now, 0), nil},
+		{"time", &jwt.Payload{ExpirationTime: exp, NotBefore: nbf, IssuedAt: iat}, jwt.TimeValidator(now, 20*time.Second), nil},
+		{"time", &jwt.Payload{ExpirationTime: exp, NotBefore: nbf, IssuedAt: iat}, jwt.TimeValidator(now, 0), jwt.ErrNbfValidation},
+		{"time", &jwt.Payload{ExpirationTime: exp, IssuedAt: iat}, jwt.TimeValidator(now.Add(-time.Minute), 0), jwt.ErrIatValidation},
+		{"time", &jwt.Payload{ExpirationTime: exp, NotBefore: nbf}, jwt.TimeValidator(now.Add(25*time.Hour), 0), jwt.ErrExpValidation},
+		{"time", &jwt.Payload{NotBefore: nbf, IssuedAt: iat}, jwt.TimeValidator(now.Add(time.Minute), 0), jwt.ErrExpValidation},
 		{"jti", &jwt.Payload{JWTID: jti}, jwt.IDValidator("jti"), nil},
 		{"jti", &jwt.Payload{JWTID: jti}, jwt.IDValidator("not_jti"), jwt.ErrJtiValidation},
 	}
@@ -57,3 +123,493 @@ func TestValidators(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorsErrorContext(t *testing.T) {
+	testCases := []struct {
+		pl   *jwt.Payload
+		vl   jwt.Validator
+		err  error
+		want string
+	}{
+		{
+			&jwt.Payload{Audience: jwt.Audience{"foo"}},
+			jwt.AudienceValidator(jwt.Audience{"bar"}),
+			jwt.ErrAudMismatch,
+			`jwt: got ["foo"], want one of ["bar"]: jwt: aud claim mismatches`,
+		},
+		{
+			&jwt.Payload{},
+			jwt.AudienceValidator(jwt.Audience{"bar"}),
+			jwt.ErrAudMissing,
+			`jwt: want one of ["bar"]: jwt: aud claim is missing`,
+		},
+		{
+			&jwt.Payload{},
+			jwt.ExpirationTimeValidator(time.Unix(1, 0)),
+			jwt.ErrExpValidation,
+			"jwt: exp is missing: jwt: exp claim is invalid",
+		},
+		{
+			&jwt.Payload{ExpirationTime: jwt.NumericDate(time.Unix(10, 0))},
+			jwt.ExpirationTimeValidator(time.Unix(20, 0)),
+			jwt.ErrExpValidation,
+			"jwt: expired at 10, now is 20: jwt: exp claim is invalid",
+		},
+		{
+			&jwt.Payload{IssuedAt: jwt.NumericDate(time.Unix(20, 0))},
+			jwt.IssuedAtValidator(time.Unix(10, 0)),
+			jwt.ErrIatValidation,
+			"jwt: issued at 20, now is 10: jwt: iat claim is invalid",
+		},
+		{
+			&jwt.Payload{Issuer: "foo"},
+			jwt.IssuerValidator("bar"),
+			jwt.ErrIssValidation,
+			`jwt: got "foo", want one of ["bar"]: jwt: iss claim is invalid`,
+		},
+		{
+			&jwt.Payload{JWTID: "foo"},
+			jwt.IDValidator("bar"),
+			jwt.ErrJtiValidation,
+			`jwt: got "foo", want "bar": jwt: jti claim is invalid`,
+		},
+		{
+			&jwt.Payload{NotBefore: jwt.NumericDate(time.Unix(20, 0))},
+			jwt.NotBeforeValidator(time.Unix(10, 0)),
+			jwt.ErrNbfValidation,
+			"jwt: not valid before 20, now is 10: jwt: nbf claim is invalid",
+		},
+		{
+			&jwt.Payload{Subject: "foo"},
+			jwt.SubjectValidator("bar"),
+			jwt.ErrSubValidation,
+			`jwt: got "foo", want "bar": jwt: sub claim is invalid`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			err := tc.vl(tc.pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf(cmp.Diff(want, got))
+			}
+			if want, got := tc.want, err.Error(); got != want {
+				t.Errorf("error message mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestCombinedValidators(t *testing.T) {
+	pl := &jwt.Payload{
+		Subject:  "admin",
+		Audience: jwt.Audience{"foo"},
+	}
+	isAdmin := func(pl *jwt.Payload) bool { return pl.Subject == "admin" }
+	not := func(f func(*jwt.Payload) bool) func(*jwt.Payload) bool {
+		return func(pl *jwt.Payload) bool { return !f(pl) }
+	}
+	testCases := []struct {
+		name string
+		vl   jwt.Validator
+		err  error
+	}{
+		{"and", jwt.AndValidator(), nil},
+		{"and", jwt.AndValidator(jwt.SubjectValidator("admin"), jwt.AudienceValidator(jwt.Audience{"foo"})), nil},
+		{"and", jwt.AndValidator(jwt.SubjectValidator("admin"), jwt.AudienceValidator(jwt.Audience{"bar"})), jwt.ErrAudValidation},
+		{"and", jwt.AndValidator(jwt.SubjectValidator("user"), jwt.AudienceValidator(jwt.Audience{"bar"})), jwt.ErrSubValidation},
+		{"or", jwt.OrValidator(), nil},
+		{"or", jwt.OrValidator(jwt.AudienceValidator(jwt.Audience{"bar"}), jwt.SubjectValidator("admin")), nil},
+		{"or", jwt.OrValidator(jwt.AudienceValidator(jwt.Audience{"foo"}), jwt.SubjectValidator("user")), nil},
+		{"or", jwt.OrValidator(jwt.AudienceValidator(jwt.Audience{"bar"}), jwt.SubjectValidator("user")), jwt.ErrSubValidation},
+		{"or", jwt.OrValidator(jwt.SubjectValidator("user"), jwt.AudienceValidator(jwt.Audience{"bar"})), jwt.ErrAudValidation},
+		{"conditional", jwt.ConditionalValidator(isAdmin, jwt.AudienceValidator(jwt.Audience{"foo"})), nil},
+		{"conditional", jwt.ConditionalValidator(isAdmin, jwt.AudienceValidator(jwt.Audience{"bar"})), jwt.ErrAudValidation},
+		{"conditional", jwt.ConditionalValidator(not(isAdmin), jwt.AudienceValidator(jwt.Audience{"bar"})), nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if want, got := tc.err, tc.vl(pl); !internal.ErrorIs(got, want) {
+				t.Errorf(cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestWildcardAudienceMatch(t *testing.T) {
+	testCases := []struct {
+		clientAud, serverAud string
+		want                 bool
+	}{
+		{"https://api.example.com", "https://api.example.com", true},
+		{"https://API.example.com", "https://api.example.com", true},
+		{"https://api.example.com", "https://*.example.com", true},
+		{"https://API.Example.com", "https://*.example.com", true},
+		{"https://example.com", "https://*.example.com", false},
+		{"https://.example.com", "https://*.example.com", false},
+		{"https://a.b.example.com", "https://*.example.com", false},
+		{"https://evil.com/.example.com", "https://*.example.com", false},
+		{"https://api.example.com.evil.com", "https://*.example.com", false},
+		{"api.example.com", "*.example.com", true},
+		{"api.example.com", "*.*.com", false},
+		{"api.example.com", "https://*.example.com", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.clientAud+" "+tc.serverAud, func(t *testing.T) {
+			if want, got := tc.want, jwt.WildcardAudienceMatch(tc.clientAud, tc.serverAud); got != want {
+				t.Errorf("jwt.WildcardAudienceMatch mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestAudienceValidatorFunc(t *testing.T) {
+	pl := &jwt.Payload{Audience: jwt.Audience{"https://API.example.com"}}
+	testCases := []struct {
+		vl  jwt.Validator
+		err error
+	}{
+		{jwt.AudienceValidator(jwt.Audience{"https://api.example.com"}), jwt.ErrAudValidation},
+		{jwt.AudienceValidatorFunc(jwt.Audience{"https://api.example.com"}, strings.EqualFold), nil},
+		{jwt.AudienceValidatorFunc(jwt.Audience{"https://*.example.com"}, jwt.WildcardAudienceMatch), nil},
+		{jwt.AudienceValidatorFunc(jwt.Audience{"https://*.example.org"}, jwt.WildcardAudienceMatch), jwt.ErrAudValidation},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			if want, got := tc.err, tc.vl(pl); !internal.ErrorIs(got, want) {
+				t.Errorf(cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestAudienceValidatorErrors(t *testing.T) {
+	testCases := []struct {
+		pl    *jwt.Payload
+		vl    jwt.Validator
+		err   error
+		other error
+	}{
+		{&jwt.Payload{}, jwt.AudienceValidator(jwt.Audience{"foo"}), jwt.ErrAudMissing, jwt.ErrAudMismatch},
+		{&jwt.Payload{Audience: jwt.Audience{"bar"}}, jwt.AudienceValidator(jwt.Audience{"foo"}), jwt.ErrAudMismatch, jwt.ErrAudMissing},
+		{&jwt.Payload{}, jwt.AudienceValidatorAll(jwt.Audience{"foo"}), jwt.ErrAudMissing, jwt.ErrAudMismatch},
+		{&jwt.Payload{Audience: jwt.Audience{"foo"}}, jwt.AudienceValidatorAll(jwt.Audience{"foo", "bar"}), jwt.ErrAudMismatch, jwt.ErrAudMissing},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			err := tc.vl(tc.pl)
+			for _, want := range []error{tc.err, jwt.ErrAudValidation} {
+				if !internal.ErrorIs(err, want) {
+					t.Errorf("err %q is not %q", err, want)
+				}
+			}
+			if internal.ErrorIs(err, tc.other) {
+				t.Errorf("err %q is %q", err, tc.other)
+			}
+		})
+	}
+}
+
+func TestRequireAudienceValidator(t *testing.T) {
+	testCases := []struct {
+		payload string
+		err     error
+	}{
+		{`{"aud":"foo"}`, nil},
+		{`{"aud":["foo","bar"]}`, nil},
+		{`{"aud":[]}`, jwt.ErrAudMissing},
+		{`{"aud":null}`, jwt.ErrAudMissing},
+		{`{}`, jwt.ErrAudMissing},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.payload, func(t *testing.T) {
+			var pl jwt.Payload
+			if err := json.Unmarshal([]byte(tc.payload), &pl); err != nil {
+				t.Fatal(err)
+			}
+			err := jwt.RequireAudienceValidator()(&pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.RequireAudienceValidator err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if tc.err != nil && err.Error() == tc.err.Error() {
+				t.Errorf("jwt.RequireAudienceValidator err = %v, want context wrapping it", err)
+			}
+		})
+	}
+}
+
+func TestClaimValidators(t *testing.T) {
+	var pl jwt.Payload
+	for name, v := range map[string]interface{}{
+		"tenant": "acme",
+		"level":  3,
+		"admin":  true,
+		"roles":  []string{"read", "write"},
+	} {
+		if err := pl.Set(name, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testCases := []struct {
+		vl  jwt.Validator
+		err error
+	}{
+		{jwt.ClaimValidator("tenant", "acme"), nil},
+		{jwt.ClaimValidator("tenant", "globex"), jwt.ErrClaimValidation},
+		{jwt.ClaimValidator("level", 3), nil},
+		{jwt.ClaimValidator("level", int64(3)), nil},
+		{jwt.ClaimValidator("level", 3.0), nil},
+		{jwt.ClaimValidator("level", "3"), jwt.ErrClaimValidation},
+		{jwt.ClaimValidator("admin", true), nil},
+		{jwt.ClaimValidator("admin", false), jwt.ErrClaimValidation},
+		{jwt.ClaimValidator("roles", []string{"read", "write"}), nil},
+		{jwt.ClaimValidator("roles", []string{"read"}), jwt.ErrClaimValidation},
+		{jwt.ClaimValidator("missing", nil), jwt.ErrClaimValidation},
+		{jwt.StringClaimValidator("tenant", "globex", "acme"), nil},
+		{jwt.StringClaimValidator("tenant", "globex"), jwt.ErrClaimValidation},
+		{jwt.StringClaimValidator("tenant"), jwt.ErrClaimValidation},
+		{jwt.StringClaimValidator("level", "3"), jwt.ErrClaimValidation},
+		{jwt.StringClaimValidator("missing", ""), jwt.ErrClaimValidation},
+		{jwt.NumericClaimValidator("level", jwt.OpEQ, 3), nil},
+		{jwt.NumericClaimValidator("level", jwt.OpNE, 3), jwt.ErrClaimValidation},
+		{jwt.NumericClaimValidator("level", jwt.OpGTE, 3), nil},
+		{jwt.NumericClaimValidator("level", jwt.OpGT, 3), jwt.ErrClaimValidation},
+		{jwt.NumericClaimValidator("level", jwt.OpGT, 2.5), nil},
+		{jwt.NumericClaimValidator("level", jwt.OpLTE, 3), nil},
+		{jwt.NumericClaimValidator("level", jwt.OpLT, 3), jwt.ErrClaimValidation},
+		{jwt.NumericClaimValidator("tenant", jwt.OpEQ, 0), jwt.ErrClaimValidation},
+		{jwt.NumericClaimValidator("missing", jwt.OpEQ, 0), jwt.ErrClaimValidation},
+		{jwt.BoolClaimValidator("admin", true), nil},
+		{jwt.BoolClaimValidator("admin", false), jwt.ErrClaimValidation},
+		{jwt.BoolClaimValidator("level", true), jwt.ErrClaimValidation},
+		{jwt.BoolClaimValidator("missing", false), jwt.ErrClaimValidation},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			if want, got := tc.err, tc.vl(&pl); !internal.ErrorIs(got, want) {
+				t.Errorf(cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestNumericClaimValidatorInvalidOp(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if want, got := jwt.ErrClaimValidation, err; !internal.ErrorIs(got, want) {
+			t.Errorf("jwt.NumericClaimValidator panic = %v, want %v", got, want)
+		}
+	}()
+	jwt.NumericClaimValidator("level", jwt.Op(-1), 0)
+}
+
+func TestReplayValidator(t *testing.T) {
+	var mu sync.Mutex
+	used := make(map[string]struct{})
+	vl := jwt.ReplayValidator(func(jti string) (bool, error) {
+		if jti == "error" {
+			return false, testErr
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, ok := used[jti]
+		used[jti] = struct{}{}
+		return ok, nil
+	})
+	testCases := []struct {
+		jti string
+		err error
+	}{
+		{"first", nil},
+		{"second", nil},
+		{"first", jwt.ErrJtiValidation},
+		{"", jwt.ErrJtiValidation},
+		{"error", testErr},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.jti, func(t *testing.T) {
+			if want, got := tc.err, vl(&jwt.Payload{JWTID: tc.jti}); !internal.ErrorIs(got, want) {
+				t.Errorf(cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestBlacklistValidator(t *testing.T) {
+	revoked := map[string]struct{}{"revoked": {}}
+	vl := jwt.BlacklistValidator(func(jti string) (bool, error) {
+		if jti == "error" {
+			return false, testErr
+		}
+		_, ok := revoked[jti]
+		return ok, nil
+	})
+	testCases := []struct {
+		jti string
+		err error
+	}{
+		{"valid", nil},
+		{"revoked", jwt.ErrJtiValidation},
+		{"", jwt.ErrJtiValidation},
+		{"error", testErr},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.jti, func(t *testing.T) {
+			if want, got := tc.err, vl(&jwt.Payload{JWTID: tc.jti}); !internal.ErrorIs(got, want) {
+				t.Errorf(cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestValidatorsNow(t *testing.T) {
+	defer func(now func() time.Time) { jwt.Now = now }(jwt.Now)
+	now := time.Now()
+	pl := &jwt.Payload{
+		ExpirationTime: jwt.NumericDate(now.Add(time.Hour)),
+		NotBefore:      jwt.NumericDate(now),
+		IssuedAt:       jwt.NumericDate(now),
+	}
+	vds := []jwt.Validator{
+		jwt.ExpirationTimeValidatorNow(0),
+		jwt.NotBeforeValidatorNow(0),
+		jwt.IssuedAtValidatorNow(0),
+	}
+	testCases := []struct {
+		now  time.Time
+		errs []error
+	}{
+		{now, []error{nil, nil, nil}},
+		{now.Add(2 * time.Hour), []error{jwt.ErrExpValidation, nil, nil}},
+		{now.Add(-time.Hour), []error{nil, jwt.ErrNbfValidation, jwt.ErrIatValidation}},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			jwt.Now = func() time.Time { return tc.now }
+			for i, vl := range vds {
+				if want, got := tc.errs[i], vl(pl); !internal.ErrorIs(got, want) {
+					t.Errorf(cmp.Diff(want, got))
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	now := time.Now()
+	pl := &jwt.Payload{
+		Issuer:         "iss",
+		Subject:        "sub",
+		ExpirationTime: jwt.NumericDate(now.Add(-time.Hour)),
+	}
+	vl := jwt.ValidateAll(
+		jwt.IssuerValidator("iss"),
+		jwt.SubjectValidator("other"),
+		jwt.ExpirationTimeValidator(now),
+		jwt.IDValidator("jti"),
+	)
+	err := vl(pl)
+	var errs jwt.ValidationErrors
+	if !internal.ErrorAs(err, &errs) {
+		t.Fatalf("jwt.ValidateAll err is %T, want jwt.ValidationErrors", err)
+	}
+	if want, got := 3, len(errs); got != want {
+		t.Errorf("jwt.ValidationErrors length mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	for _, want := range []error{jwt.ErrSubValidation, jwt.ErrExpValidation, jwt.ErrJtiValidation} {
+		if !internal.ErrorIs(err, want) {
+			t.Errorf("jwt.ValidationErrors doesn't match %v", want)
+		}
+	}
+	if internal.ErrorIs(err, jwt.ErrIssValidation) {
+		t.Errorf("jwt.ValidationErrors matches %v", jwt.ErrIssValidation)
+	}
+	if err = jwt.ValidateAll(jwt.IssuerValidator("iss"))(pl); err != nil {
+		t.Errorf("jwt.ValidateAll err mismatch (-want +got):\n%s", cmp.Diff(nil, err))
+	}
+	if err = jwt.ValidateAll()(pl); err != nil {
+		t.Errorf("jwt.ValidateAll err mismatch (-want +got):\n%s", cmp.Diff(nil, err))
+	}
+}
+
+func TestValidatorFactory(t *testing.T) {
+	defer func(now func() time.Time) { jwt.Now = now }(jwt.Now)
+	now := time.Now()
+	pl := &jwt.Payload{
+		ExpirationTime: jwt.NumericDate(now),
+		NotBefore:      jwt.NumericDate(now),
+		IssuedAt:       jwt.NumericDate(now),
+	}
+	testCases := []struct {
+		name string
+		vf   *jwt.ValidatorFactory
+		now  time.Time
+		errs []error
+	}{
+		{"no leeway after", jwt.Validators(), now.Add(time.Minute), []error{jwt.ErrExpValidation, nil, nil, jwt.ErrExpValidation}},
+		{"no leeway before", jwt.Validators(), now.Add(-time.Minute), []error{nil, jwt.ErrNbfValidation, jwt.ErrIatValidation, jwt.ErrNbfValidation}},
+		{"leeway after", jwt.Validators(jwt.WithLeeway(2 * time.Minute)), now.Add(time.Minute), []error{nil, nil, nil, nil}},
+		{"leeway before", jwt.Validators(jwt.WithLeeway(2 * time.Minute)), now.Add(-time.Minute), []error{nil, nil, nil, nil}},
+		{"negative leeway", jwt.Validators(jwt.WithLeeway(-time.Hour)), now, []error{nil, nil, nil, nil}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jwt.Now = func() time.Time { return tc.now }
+			vds := []jwt.Validator{tc.vf.ExpirationTime(), tc.vf.NotBefore(), tc.vf.IssuedAt(), tc.vf.Time()}
+			for i, vd := range vds {
+				if want, got := tc.errs[i], vd(pl); !internal.ErrorIs(got, want) {
+					t.Errorf("validator %d err mismatch (-want +got):\n%s", i, cmp.Diff(want, got))
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorsTimeZones(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		saoPaulo = time.FixedZone("-03", -3*60*60) // no time zone database available
+	}
+	// Brazil observed DST until 2019, so this reference is within a DST period in São Paulo.
+	ref := time.Date(2018, time.December, 1, 12, 0, 0, 0, time.UTC)
+	pl := &jwt.Payload{
+		ExpirationTime: jwt.NumericDate(ref.Add(time.Hour)),
+		NotBefore:      jwt.NumericDate(ref.In(saoPaulo)),
+		IssuedAt:       jwt.NumericDate(ref.Local()),
+	}
+	for _, claim := range []*jwt.Time{pl.ExpirationTime, pl.NotBefore, pl.IssuedAt} {
+		if want, got := time.UTC, claim.Location(); got != want {
+			t.Errorf("jwt.NumericDate location mismatch (-want +got):\n%s", cmp.Diff(want.String(), got.String()))
+		}
+	}
+	offsets := []time.Duration{-time.Minute, 0, 30 * time.Minute, time.Hour, time.Hour + time.Second}
+	for _, offset := range offsets {
+		now := ref.Add(offset)
+		vds := func(now time.Time) []jwt.Validator {
+			return []jwt.Validator{
+				jwt.ExpirationTimeValidator(now),
+				jwt.NotBeforeValidator(now),
+				jwt.IssuedAtValidator(now),
+				jwt.TimeValidator(now, 0),
+			}
+		}
+		utc, local := vds(now.UTC()), vds(now.In(saoPaulo))
+		for i := range utc {
+			if want, got := timeErrKind(utc[i](pl)), timeErrKind(local[i](pl)); got != want {
+				t.Errorf("validator %d at %v: UTC and America/Sao_Paulo mismatch (-want +got):\n%s", i, offset, cmp.Diff(want, got))
+			}
+		}
+	}
+}
+
+// timeErrKind returns the message of the temporal validation error err wraps, if any.
+func timeErrKind(err error) string {
+	for _, kind := range []error{jwt.ErrExpValidation, jwt.ErrNbfValidation, jwt.ErrIatValidation} {
+		if internal.ErrorIs(err, kind) {
+			return kind.Error()
+		}
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
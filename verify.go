@@ -1,51 +1,224 @@
 package jwt
 
 import (
-	"bytes"
+	"context"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/gbrlsnchs/jwt/v3/internal"
 )
 
-// ErrAlgValidation indicates an incoming JWT's "alg" field mismatches the Validator's.
-var ErrAlgValidation = internal.NewError(`invalid "alg" field`)
+var (
+	// ErrAlgValidation indicates an incoming JWT's "alg" field mismatches the Validator's.
+	ErrAlgValidation = internal.NewError(`invalid "alg" field`)
+	// ErrAlgNone indicates an incoming JWT is unsecured but alg is not the "none" algorithm.
+	ErrAlgNone = internal.NewError(`jwt: "none" algorithm is not allowed`)
+	// ErrCritValidation indicates an incoming JWT's "crit" field lists parameters that are not understood.
+	ErrCritValidation = internal.NewError(`jwt: invalid "crit" field`)
+	// ErrSignatureMismatch indicates an incoming JWT's signature is invalid.
+	// The verification errors of all algorithms wrap it.
+	ErrSignatureMismatch = internal.NewError("jwt: signature mismatch")
+	// ErrPayloadNotEmbedded indicates a destination for VerifyInto doesn't embed Payload.
+	ErrPayloadNotEmbedded = internal.NewError("jwt: Payload is not embedded")
+	// ErrHeaderParamNotAllowed indicates an incoming JWT's header has a parameter not allowed
+	// by AllowedHeaderParams.
+	ErrHeaderParamNotAllowed = internal.NewError("jwt: header parameter is not allowed")
+	// ErrTypValidation indicates an incoming JWT's "typ" field mismatches the expected ones.
+	ErrTypValidation = internal.NewError(`jwt: invalid "typ" field`)
+)
 
 // VerifyOption is a functional option for verifying.
+//
+// Options are run before the token is decoded, so the limits set by MaxTokenSize and MaxHeaderSize
+// apply before any decoding work is done. Options checking the header, such as ValidateHeaderParams,
+// defer their checks until it's decoded.
 type VerifyOption func(*RawToken) error
 
 // Verify verifies a token's signature using alg. Before verification, opts is iterated and
 // each option in it is run.
+//
+// It returns the token's decoded Header, so "alg", "typ", "kid" and "cty" can be inspected
+// without decoding the token again. The Header is returned even if verification fails,
+// as long as it could be decoded. When verification succeeds, its Algorithm field is confirmed
+// to be the name of the algorithm that verified the signature, so it can be logged for auditing
+// or checked against weak algorithms.
+//
+// Tokens whose "alg" header parameter is "none" are rejected with ErrAlgNone, before any header check
+// or validator is run, unless alg is the one returned by None. Otherwise, an attacker could strip a token's
+// signature and downgrade it to an unsecured one that a lenient algorithm would accept.
+//
+// Tokens listing extension parameters in the "crit" header parameter are rejected with ErrCritValidation
+// unless all of them are registered as understood with the CriticalParams option.
+//
+// The "alg" header parameter must also match alg's name, after alg is resolved when it's a Resolver,
+// otherwise ErrAlgValidation is returned. This prevents algorithm confusion attacks, such as an attacker
+// using an RSA public key as an HMAC secret to forge a token declared as "HS256".
+//
+// Errors for tokens that can't be decoded wrap ErrMalformed, errors for invalid signatures
+// wrap ErrSignatureMismatch and errors from validators are returned unchanged,
+// so each case can be told apart.
+//
+// Validators only run after the signature is verified and payload is decoded, so when one of them fails,
+// payload still holds the token's claims, for example, for logging the subject of a rejected token.
+// Those claims are authentic, since they were signed, but they must not be trusted as valid.
 func Verify(token []byte, alg Algorithm, payload interface{}, opts ...VerifyOption) (Header, error) {
-	rt := &RawToken{
-		alg: alg,
-	}
+	return verify(context.Background(), token, alg, payload, opts)
+}
+
+// VerifyContext is like Verify, but passes ctx to alg when it's a ContextResolver,
+// so resolving it, for example by fetching keys over the network, can be cancelled.
+// For other algorithms, ctx is only checked before verifying.
+func VerifyContext(ctx context.Context, token []byte, alg Algorithm, payload interface{}, opts ...VerifyOption) (Header, error) {
+	return verify(ctx, token, alg, payload, opts)
+}
 
-	sep1 := bytes.IndexByte(token, '.')
-	if sep1 < 0 {
-		return rt.hd, ErrMalformed
+// VerifyInto is like Verify, but validators are run against the Payload embedded in dst,
+// which is usually a pointer to a struct with custom claims that embeds Payload:
+//
+//	type MyClaims struct {
+//		jwt.Payload
+//		Roles []string `json:"roles,omitempty"`
+//	}
+//
+// dst may also be a *Payload. If validators are passed and dst doesn't embed Payload,
+// ErrPayloadNotEmbedded is returned before anything is verified.
+func VerifyInto(token []byte, alg Algorithm, dst interface{}, vds ...Validator) (Header, error) {
+	var opts []VerifyOption
+	if len(vds) > 0 {
+		pl := embeddedPayload(dst)
+		if pl == nil {
+			return Header{}, ErrPayloadNotEmbedded
+		}
+		opts = append(opts, ValidatePayload(pl, vds...))
 	}
+	return verify(context.Background(), token, alg, dst, opts)
+}
 
-	cbytes := token[sep1+1:]
-	sep2 := bytes.IndexByte(cbytes, '.')
-	if sep2 < 0 {
-		return rt.hd, ErrMalformed
+// VerifyWithExpiry is like VerifyInto with a Payload, but also returns the "exp" claim,
+// so verification results can be cached until the token expires.
+// If the token has no "exp" claim, the zero time is returned.
+func VerifyWithExpiry(token []byte, alg Algorithm, vds ...Validator) (Payload, time.Time, error) {
+	var pl Payload
+	if _, err := VerifyInto(token, alg, &pl, vds...); err != nil {
+		return Payload{}, time.Time{}, err
+	}
+	var exp time.Time
+	if pl.ExpirationTime != nil {
+		exp = pl.ExpirationTime.Time
 	}
-	rt.setToken(token, sep1, sep2)
+	return pl, exp, nil
+}
 
-	var err error
-	if err = rt.decodeHeader(); err != nil {
-		return rt.hd, err
+// embeddedPayload returns dst if it's a *Payload or a pointer to its embedded Payload, if any.
+func embeddedPayload(dst interface{}) *Payload {
+	if pl, ok := dst.(*Payload); ok {
+		return pl
 	}
-	if rv, ok := alg.(Resolver); ok {
-		if err = rv.Resolve(rt.hd); err != nil {
-			return rt.hd, err
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).Anonymous {
+			continue
+		}
+		switch f := v.Field(i); f.Type() {
+		case reflect.TypeOf(Payload{}):
+			return f.Addr().Interface().(*Payload)
+		case reflect.TypeOf(&Payload{}):
+			if f.IsNil() {
+				f.Set(reflect.New(f.Type().Elem()))
+			}
+			return f.Interface().(*Payload)
 		}
 	}
+	return nil
+}
+
+func verify(ctx context.Context, token []byte, alg Algorithm, payload interface{}, opts []VerifyOption) (Header, error) {
+	hook := OnVerify
+	var start time.Time
+	if hook != nil {
+		start = time.Now()
+	}
+	rt, err := verifySignature(ctx, token, alg, opts)
+	verified := err == nil
+	if verified {
+		err = rt.decode(payload)
+	}
+	if hook != nil {
+		reportVerify(hook, rt, verified, err, start)
+	}
+	return rt.hd, err
+}
+
+// verifySignature runs all checks and options, then verifies token's signature.
+// The returned RawToken is never nil, so its header can be returned along with an error.
+func verifySignature(ctx context.Context, token []byte, alg Algorithm, opts []VerifyOption) (*RawToken, error) {
+	rt := &RawToken{
+		alg: alg,
+
+		maxTokenSize:  DefaultMaxTokenSize,
+		maxHeaderSize: DefaultMaxHeaderSize,
+	}
+	var err error
+	if err = rt.split(token); err != nil {
+		return rt, err
+	}
 	for _, opt := range opts {
 		if err = opt(rt); err != nil {
-			return rt.hd, err
+			return rt, err
 		}
 	}
-	if err = alg.Verify(rt.headerPayload(), rt.sig()); err != nil {
+	if err = checkSize(token, rt.maxTokenSize, rt.maxHeaderSize); err != nil {
+		return rt, err
+	}
+	if err = rt.decodeHeader(); err != nil {
+		return rt, err
+	}
+	if _, ok := alg.(none); !ok && strings.EqualFold(rt.hd.Algorithm, "none") {
+		return rt, internal.Errorf("jwt: %q: %w", rt.hd.Algorithm, ErrAlgNone)
+	}
+	if err = ctx.Err(); err != nil {
+		return rt, err
+	}
+	if err = resolve(ctx, alg, rt.hd); err != nil {
+		return rt, err
+	}
+	if err = ValidateHeader(rt); err != nil {
+		return rt, err
+	}
+	if err = rt.checkHeader(); err != nil {
+		return rt, err
+	}
+	if err = rt.validateCritical(); err != nil {
+		return rt, err
+	}
+	if err = alg.Verify(rt.signingInput(), rt.sig()); err != nil {
+		var cie base64.CorruptInputError
+		if internal.ErrorAs(err, &cie) {
+			return rt, malformed(err)
+		}
+		return rt, err
+	}
+	return rt, nil
+}
+
+// ParseUnverified decodes a token's header and payload WITHOUT verifying its signature
+// or running any validators.
+//
+// WARNING: the returned Header and the decoded payload are untrusted, since anyone can craft them.
+// Only use them for debugging or for choosing how to verify the token, for example by looking up
+// a key by "iss" or "kid", and then always call Verify before trusting any of the claims.
+func ParseUnverified(token []byte, payload interface{}) (Header, error) {
+	var rt RawToken
+	if err := checkSize(token, DefaultMaxTokenSize, DefaultMaxHeaderSize); err != nil {
+		return rt.hd, err
+	}
+	if err := rt.parse(token); err != nil {
 		return rt.hd, err
 	}
 	return rt.hd, rt.decode(payload)
@@ -53,13 +226,104 @@ func Verify(token []byte, alg Algorithm, payload interface{}, opts ...VerifyOpti
 
 // ValidateHeader checks whether the algorithm contained
 // in the JOSE header is the same used by the algorithm.
+//
+// Verify always runs this check, so passing it as an option is no longer needed.
+// When there's no algorithm, as in DecryptClaims, the check always fails.
 func ValidateHeader(rt *RawToken) error {
-	if rt.alg.Name() != rt.hd.Algorithm {
+	if rt.alg == nil || rt.alg.Name() != rt.hd.Algorithm {
 		return internal.Errorf("jwt: %q: %w", rt.hd.Algorithm, ErrAlgValidation)
 	}
 	return nil
 }
 
+// CriticalParams registers header parameters as understood, so tokens listing them
+// in the "crit" header parameter are accepted by Verify.
+// Checking the parameters themselves is up to the caller.
+func CriticalParams(params ...string) VerifyOption {
+	return func(rt *RawToken) error {
+		rt.critParams = append(rt.critParams, params...)
+		return nil
+	}
+}
+
+// AllowedHeaderParams rejects tokens whose header has parameters other than "alg", "typ", "cty", "kid"
+// and params, with ErrHeaderParamNotAllowed. It's stricter than the "crit" header parameter, since any
+// unexpected parameter is rejected, for profiles that want to minimize the accepted surface.
+func AllowedHeaderParams(params ...string) VerifyOption {
+	allowed := map[string]struct{}{"alg": {}, "typ": {}, "cty": {}, "kid": {}}
+	for _, param := range params {
+		allowed[param] = struct{}{}
+	}
+	return afterHeader(func(rt *RawToken) error {
+		for _, name := range rt.hdParams {
+			if _, ok := allowed[name]; !ok {
+				return internal.Errorf("jwt: %q: %w", name, ErrHeaderParamNotAllowed)
+			}
+		}
+		return nil
+	})
+}
+
+// ValidateType checks whether the "typ" header parameter is one of types, preventing a token
+// of one type from being accepted as another, for example an access token as an ID token.
+//
+// Types are compared case-insensitively and the "application/" prefix is ignored, as per the RFC 7515.
+func ValidateType(types ...string) VerifyOption {
+	return afterHeader(func(rt *RawToken) error {
+		typ := trimMediaType(rt.hd.Type)
+		for _, want := range types {
+			if strings.EqualFold(typ, trimMediaType(want)) {
+				return nil
+			}
+		}
+		return internal.Errorf("jwt: %q: %w", rt.hd.Type, ErrTypValidation)
+	})
+}
+
+func trimMediaType(typ string) string {
+	const prefix = "application/"
+	if len(typ) > len(prefix) && strings.EqualFold(typ[:len(prefix)], prefix) {
+		return typ[len(prefix):]
+	}
+	return typ
+}
+
+// DisallowDuplicateKeys makes verification fail with ErrDuplicateKey when the header or the payload
+// have JSON objects with duplicate keys, which encoding/json otherwise accepts by keeping the last value.
+// Rejecting them prevents an attacker from exploiting parsers that disagree on which value wins.
+func DisallowDuplicateKeys() VerifyOption {
+	return func(rt *RawToken) error {
+		rt.strictJSON = true
+		return nil
+	}
+}
+
+// HeaderValidator is a function that validates a Header pointer.
+type HeaderValidator func(*Header) error
+
+// ValidateHeaderParams runs validators against the decoded Header, for example,
+// for requiring the "kid" header parameter. Like other options, they run before
+// the signature is verified, so their errors are returned as is.
+func ValidateHeaderParams(vds ...HeaderValidator) VerifyOption {
+	return afterHeader(func(rt *RawToken) error {
+		for _, vd := range vds {
+			if err := vd(&rt.hd); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// afterHeader returns an option deferring check until the token's header is decoded,
+// since options are run before that.
+func afterHeader(check VerifyOption) VerifyOption {
+	return func(rt *RawToken) error {
+		rt.hdChecks = append(rt.hdChecks, check)
+		return nil
+	}
+}
+
 // ValidatePayload runs validators against a Payload after it's been decoded.
 func ValidatePayload(pl *Payload, vds ...Validator) VerifyOption {
 	return func(rt *RawToken) error {
@@ -69,5 +333,87 @@ func ValidatePayload(pl *Payload, vds ...Validator) VerifyOption {
 	}
 }
 
+// RawValidator is a function that validates the decoded payload of a token, as JSON.
+type RawValidator func(payload []byte) error
+
+// ValidateRawPayload runs validators against the decoded payload JSON, including members
+// that neither Payload nor the struct it's decoded into model. Like the validators passed to
+// ValidatePayload, they run after the signature is verified and the payload is decoded.
+func ValidateRawPayload(vds ...RawValidator) VerifyOption {
+	return func(rt *RawToken) error {
+		rt.rawVds = vds
+		return nil
+	}
+}
+
+// MaxTokenSize sets the maximum byte size of a token accepted by Verify or DecryptClaims,
+// overriding DefaultMaxTokenSize, for example, for tokens carrying many claims. Zero means no limit.
+// The size is checked right after options are run, before any part of the token is decoded.
+func MaxTokenSize(n int) VerifyOption {
+	return func(rt *RawToken) error {
+		rt.maxTokenSize = n
+		return nil
+	}
+}
+
+// MaxHeaderSize is like MaxTokenSize, but sets the maximum byte size of a token's encoded header,
+// overriding DefaultMaxHeaderSize, for example, for headers with long "x5c" chains.
+func MaxHeaderSize(n int) VerifyOption {
+	return func(rt *RawToken) error {
+		rt.maxHeaderSize = n
+		return nil
+	}
+}
+
+// SignatureOnly guarantees no validators are run against the payload, even if ValidatePayload
+// or ValidateRawPayload are also passed, so Verify only checks the token's format, header and signature.
+// Note that Verify never runs validators implicitly, so this only makes that explicit.
+//
+// The caller assumes responsibility for validating all claims, including "exp" and "nbf".
+func SignatureOnly() VerifyOption {
+	return func(rt *RawToken) error {
+		rt.signatureOnly = true
+		return nil
+	}
+}
+
+// ReportValidatorIndex makes errors from the validators passed to ValidatePayload be returned
+// as a *ValidatorError holding the failing validator's index, for mapping failures back to policy rules.
+// The original errors can still be checked with errors.Is and errors.As.
+func ReportValidatorIndex() VerifyOption {
+	return func(rt *RawToken) error {
+		rt.reportIndex = true
+		return nil
+	}
+}
+
+func (rt *RawToken) validateCritical() error {
+	crit := rt.hd.Critical
+	if crit == nil {
+		return nil
+	}
+	if len(crit) == 0 {
+		return internal.Errorf("jwt: empty list: %w", ErrCritValidation)
+	}
+	for _, param := range crit {
+		if _, ok := headerParams[param]; ok && param != "b64" {
+			return internal.Errorf("jwt: %q is a registered parameter: %w", param, ErrCritValidation)
+		}
+		if !rt.understands(param) {
+			return internal.Errorf("jwt: %q is not understood: %w", param, ErrCritValidation)
+		}
+	}
+	return nil
+}
+
+func (rt *RawToken) understands(param string) bool {
+	for _, understood := range rt.critParams {
+		if param == understood {
+			return true
+		}
+	}
+	return false
+}
+
 // Compile-time checks.
 var _ VerifyOption = ValidateHeader
@@ -1,12 +1,19 @@
 package jwt_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gbrlsnchs/jwt/v3"
 	"github.com/gbrlsnchs/jwt/v3/internal"
+	"github.com/gbrlsnchs/jwt/v3/jwtutil"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -64,7 +71,7 @@ func TestVerify(t *testing.T) {
 					}
 					var pl testPayload
 					hd, err := jwt.Verify(token, tc.verifyAlg, &pl)
-					if want, got := tc.verifyErr, err; got != want {
+					if want, got := tc.verifyErr, err; !internal.ErrorIs(got, want) {
 						t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
 					}
 					if want, got := tc.wantHeader, hd; !cmp.Equal(got, want) {
@@ -92,6 +99,585 @@ func TestVerify(t *testing.T) {
 	})
 }
 
+func TestVerifyErrorCategories(t *testing.T) {
+	hs256 := jwt.NewHS256(hmacKey1)
+	signed := func(alg jwt.Algorithm) string {
+		token, err := jwt.Sign(tp, alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(token)
+	}
+	segment := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	header := segment(`{"alg":"HS256","typ":"JWT"}`)
+	// Payloads are only decoded after their signature is verified.
+	withSig := func(headerPayload string) string {
+		sig, err := hs256.Sign([]byte(headerPayload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return headerPayload + "." + base64.RawURLEncoding.EncodeToString(sig)
+	}
+
+	testCases := []struct {
+		name      string
+		token     string
+		verifyAlg jwt.Algorithm
+		opts      []jwt.VerifyOption
+		err       error
+	}{
+		{"separators", "foo.bar", hs256, nil, jwt.ErrMalformed},
+		{"header encoding", "!!!." + segment(`{}`) + ".", hs256, nil, jwt.ErrMalformed},
+		{"header JSON", segment(`{"alg":`) + "." + segment(`{}`) + ".", hs256, nil, jwt.ErrMalformed},
+		{"payload encoding", withSig(header + ".!!!"), hs256, nil, jwt.ErrMalformed},
+		{"payload JSON", withSig(header + "." + segment(`{"sub":`)), hs256, nil, jwt.ErrMalformed},
+		{"empty payload", withSig(header + "."), hs256, nil, jwt.ErrMalformed},
+		{"signature encoding", header + "." + segment(`{}`) + ".!!!", hs256, nil, jwt.ErrMalformed},
+		{"HMAC", signed(jwt.NewHS256(hmacKey2)), hs256, nil, jwt.ErrSignatureMismatch},
+		{"RSA", signed(jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey2))), jwt.NewRS256(jwt.RSAPublicKey(rsaPublicKey1)), nil, jwt.ErrSignatureMismatch},
+		{"ECDSA", signed(jwt.NewES256(jwt.ECDSAPrivateKey(es256PrivateKey2))), jwt.NewES256(jwt.ECDSAPublicKey(es256PublicKey1)), nil, jwt.ErrSignatureMismatch},
+		{"Ed25519", signed(jwt.NewEd25519(jwt.Ed25519PrivateKey(ed25519PrivateKey2))), jwt.NewEd25519(jwt.Ed25519PublicKey(ed25519PublicKey1)), nil, jwt.ErrSignatureMismatch},
+		{"validator", signed(hs256), hs256, []jwt.VerifyOption{jwt.ValidatePayload(&tp.Payload, jwt.SubjectValidator("other"))}, jwt.ErrSubValidation},
+	}
+	categories := []error{jwt.ErrMalformed, jwt.ErrSignatureMismatch, jwt.ErrSubValidation}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pl testPayload
+			_, err := jwt.Verify([]byte(tc.token), tc.verifyAlg, &pl, tc.opts...)
+			for _, category := range categories {
+				if want, got := category == tc.err, internal.ErrorIs(err, category); got != want {
+					t.Errorf("jwt.Verify err %q is %q mismatch (-want +got):\n%s", err, category, cmp.Diff(want, got))
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyAlgConfusion(t *testing.T) {
+	der, err := x509.MarshalPKIXPublicKey(rsaPublicKey1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The attacker uses the public key as an HMAC secret.
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	forged, err := jwt.Sign(jwt.Payload{Subject: "admin"}, jwt.NewHS256(pubPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pl jwt.Payload
+	_, err = jwt.Verify(forged, jwt.NewRS256(jwt.RSAPublicKey(rsaPublicKey1)), &pl)
+	if want, got := jwt.ErrAlgValidation, err; !internal.ErrorIs(got, want) {
+		t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := (jwt.Payload{}), pl; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestVerifyHeader(t *testing.T) {
+	token, err := jwt.Sign(jwt.Payload{}, jwt.NewHS256(hmacKey1), jwt.ContentType("JWT"), jwt.KeyID("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		alg jwt.Algorithm
+		err error
+	}{
+		{jwt.NewHS256(hmacKey1), nil},
+		{jwt.NewHS256(hmacKey2), jwt.ErrHMACVerification},
+		{jwt.NewHS512(hmacKey1), jwt.ErrAlgValidation},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.alg.Name(), func(t *testing.T) {
+			var pl jwt.Payload
+			hd, err := jwt.Verify(token, tc.alg, &pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			want := jwt.Header{Algorithm: "HS256", ContentType: "JWT", KeyID: "test", Type: "JWT"}
+			if got := hd; !cmp.Equal(got, want) {
+				t.Errorf("jwt.Verify header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestVerifyAlgorithmName(t *testing.T) {
+	allowed := map[string]jwt.Algorithm{
+		"RS256": jwt.NewRS256(jwt.RSAPublicKey(rsaPublicKey1)),
+		"PS256": jwt.NewPS256(jwt.RSAPublicKey(rsaPublicKey1)),
+	}
+	resolve := func(hd jwt.Header) (jwt.Algorithm, error) {
+		if alg, ok := allowed[hd.Algorithm]; ok {
+			return alg, nil
+		}
+		return jwt.NewRS512(jwt.RSAPublicKey(rsaPublicKey1)), nil
+	}
+	testCases := []struct {
+		signer jwt.Algorithm
+		want   string
+		err    error
+	}{
+		{jwt.NewRS256(jwt.RSAPrivateKey(rsaPrivateKey1)), "RS256", nil},
+		{jwt.NewPS256(jwt.RSAPrivateKey(rsaPrivateKey1)), "PS256", nil},
+		{jwt.NewRS384(jwt.RSAPrivateKey(rsaPrivateKey1)), "RS384", jwt.ErrAlgValidation},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.signer.Name(), func(t *testing.T) {
+			token, err := jwt.Sign(jwt.Payload{}, tc.signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rv := &jwtutil.Resolver{New: resolve}
+			hd, err := jwt.Verify(token, rv, &jwt.Payload{})
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.want, hd.Algorithm; got != want {
+				t.Errorf("jwt.Header.Algorithm mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if err == nil && hd.Algorithm != rv.Name() {
+				t.Errorf("jwt.Header.Algorithm = %q, verified with %q", hd.Algorithm, rv.Name())
+			}
+		})
+	}
+}
+
+func TestVerifyContext(t *testing.T) {
+	token, err := jwt.Sign(jwt.Payload{}, jwt.NewHS256(hmacKey1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	testCases := []struct {
+		ctx context.Context
+		alg jwt.Algorithm
+		err error
+	}{
+		{context.Background(), jwt.NewHS256(hmacKey1), nil},
+		{canceled, jwt.NewHS256(hmacKey1), context.Canceled},
+		{
+			context.Background(),
+			&jwtutil.Resolver{NewContext: func(ctx context.Context, _ jwt.Header) (jwt.Algorithm, error) {
+				return jwt.NewHS256(hmacKey1), ctx.Err()
+			}},
+			nil,
+		},
+		{
+			context.Background(),
+			&jwtutil.Resolver{New: func(_ jwt.Header) (jwt.Algorithm, error) {
+				return jwt.NewHS256(hmacKey1), nil
+			}},
+			nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			var pl jwt.Payload
+			_, err := jwt.VerifyContext(tc.ctx, token, tc.alg, &pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.VerifyContext err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestParseUnverified(t *testing.T) {
+	token, err := jwt.Sign(tp, jwt.NewHS256(hmacKey1), jwt.KeyID("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		token      []byte
+		wantHeader jwt.Header
+		wantPl     testPayload
+		err        error
+	}{
+		{token, jwt.Header{Algorithm: "HS256", KeyID: "test", Type: "JWT"}, tp, nil},
+		{token[:len(token)-1], jwt.Header{Algorithm: "HS256", KeyID: "test", Type: "JWT"}, tp, nil},
+		{[]byte("foo"), jwt.Header{}, testPayload{}, jwt.ErrMalformed},
+		{[]byte("foo.bar"), jwt.Header{}, testPayload{}, jwt.ErrMalformed},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.token), func(t *testing.T) {
+			var pl testPayload
+			hd, err := jwt.ParseUnverified(tc.token, &pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.ParseUnverified err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantHeader, hd; !cmp.Equal(got, want) {
+				t.Errorf("jwt.ParseUnverified header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantPl, pl; !cmp.Equal(got, want) {
+				t.Errorf("jwt.ParseUnverified payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestVerifyPadded(t *testing.T) {
+	// Some issuers wrongly pad every part of their tokens.
+	alg := jwt.NewHS256(hmacKey1)
+	headerPayload := base64.URLEncoding.EncodeToString([]byte(`{"alg":"HS256","kid":"x","typ":"JWT"}`)) + "." +
+		base64.URLEncoding.EncodeToString([]byte(`{"sub":"someone"}`))
+	sig, err := alg.Sign([]byte(headerPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := headerPayload + "." + base64.URLEncoding.EncodeToString(sig)
+	if !strings.Contains(token, "=") {
+		t.Fatalf("token is not padded: %s", token)
+	}
+
+	var pl jwt.Payload
+	hd, err := jwt.Verify([]byte(token), alg, &pl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := (jwt.Header{Algorithm: "HS256", KeyID: "x", Type: "JWT"}), hd; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := (jwt.Payload{Subject: "someone"}), pl; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	// Tokens are never padded when signing.
+	signed, err := jwt.Sign(pl, alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.IndexByte(signed, '=') >= 0 {
+		t.Errorf("jwt.Sign output is padded: %s", signed)
+	}
+}
+
+func TestVerifyAlphabet(t *testing.T) {
+	alg := jwt.NewHS256(hmacKey1)
+	withSig := func(headerPayload string) []byte {
+		sig, err := alg.Sign([]byte(headerPayload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []byte(headerPayload + "." + base64.RawURLEncoding.EncodeToString(sig))
+	}
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"~~~"}`)) // contains "-" and "_"
+	testCases := []struct {
+		name  string
+		token []byte
+		err   error
+	}{
+		{"URL alphabet", withSig(header + "." + payload), nil},
+		{"standard alphabet", withSig(header + "." + base64.StdEncoding.EncodeToString([]byte(`{"sub":"~~~"}`))), jwt.ErrMalformed},
+		{"line break", withSig(header + "." + payload[:4] + "\n" + payload[4:]), jwt.ErrMalformed},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pl jwt.Payload
+			_, err := jwt.Verify(tc.token, alg, &pl)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestVerifyOriginalSigningInput(t *testing.T) {
+	alg := jwt.NewHS256(hmacKey1)
+	// Neither the key order nor the whitespace are the ones Sign would produce,
+	// so re-encoding the header or the payload would change the signing input.
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{ "typ": "JWT", "alg": "HS256" }`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"sub",  "iss":"iss","aud":["aud"]}`))
+	sig, err := alg.Sign([]byte(header + "." + payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := []byte(header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(sig))
+	var pl jwt.Payload
+	if _, err = jwt.Verify(token, alg, &pl); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := (jwt.Payload{Issuer: "iss", Subject: "sub", Audience: jwt.Audience{"aud"}}), pl; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestVerifyInto(t *testing.T) {
+	type claims struct {
+		jwt.Payload
+		Roles []string `json:"roles,omitempty"`
+	}
+	type ptrClaims struct {
+		*jwt.Payload
+		Roles []string `json:"roles,omitempty"`
+	}
+	alg := jwt.NewHS256(hmacKey1)
+	want := claims{Payload: jwt.Payload{Subject: "someone"}, Roles: []string{"admin"}}
+	token, err := jwt.Sign(want, alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		dst  interface{}
+		vds  []jwt.Validator
+		err  error
+	}{
+		{"embedded", &claims{}, []jwt.Validator{jwt.SubjectValidator("someone")}, nil},
+		{"embedded invalid", &claims{}, []jwt.Validator{jwt.SubjectValidator("other")}, jwt.ErrSubValidation},
+		{"embedded pointer", &ptrClaims{}, []jwt.Validator{jwt.SubjectValidator("other")}, jwt.ErrSubValidation},
+		{"payload", &jwt.Payload{}, []jwt.Validator{jwt.SubjectValidator("other")}, jwt.ErrSubValidation},
+		{"not embedded", &map[string]interface{}{}, []jwt.Validator{jwt.SubjectValidator("someone")}, jwt.ErrPayloadNotEmbedded},
+		{"no validators", &map[string]interface{}{}, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := jwt.VerifyInto(token, alg, tc.dst, tc.vds...)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.VerifyInto err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+
+	var got claims
+	if _, err = jwt.VerifyInto(token, alg, &got, jwt.SubjectValidator("someone")); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("jwt.VerifyInto mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestVerifyWithExpiry(t *testing.T) {
+	alg := jwt.NewHS256(hmacKey1)
+	exp := time.Unix(2000000000, 0)
+	testCases := []struct {
+		name    string
+		pl      jwt.Payload
+		vds     []jwt.Validator
+		wantExp time.Time
+		err     error
+	}{
+		{"exp", jwt.Payload{Subject: "sub", ExpirationTime: jwt.NumericDate(exp)}, []jwt.Validator{jwt.SubjectValidator("sub")}, exp, nil},
+		{"no exp", jwt.Payload{Subject: "sub"}, nil, time.Time{}, nil},
+		{"invalid", jwt.Payload{ExpirationTime: jwt.NumericDate(exp)}, []jwt.Validator{jwt.SubjectValidator("sub")}, time.Time{}, jwt.ErrSubValidation},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := jwt.Sign(tc.pl, alg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pl, gotExp, err := jwt.VerifyWithExpiry(token, alg, tc.vds...)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.VerifyWithExpiry err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if want, got := tc.wantExp, gotExp; !got.Equal(want) {
+				t.Errorf("jwt.VerifyWithExpiry exp mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if err == nil && !cmp.Equal(pl, tc.pl) {
+				t.Errorf("jwt.VerifyWithExpiry payload mismatch (-want +got):\n%s", cmp.Diff(tc.pl, pl))
+			}
+		})
+	}
+}
+
+func TestVerifyTokenSize(t *testing.T) {
+	alg := jwt.NewHS256(hmacKey1)
+	large, err := jwt.Sign(testPayload{String: strings.Repeat("a", jwt.DefaultMaxTokenSize)}, alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	largeHeader, err := jwt.Sign(tp, alg, jwt.KeyID(strings.Repeat("a", jwt.DefaultMaxHeaderSize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	small, err := jwt.Sign(tp, alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		token []byte
+		opts  []jwt.VerifyOption
+		err   error
+	}{
+		{large, nil, jwt.ErrTokenTooLarge},
+		{largeHeader, nil, jwt.ErrTokenTooLarge},
+		{large, []jwt.VerifyOption{jwt.MaxTokenSize(0)}, nil},
+		{large, []jwt.VerifyOption{jwt.MaxTokenSize(2 * len(large))}, nil},
+		{largeHeader, []jwt.VerifyOption{jwt.MaxHeaderSize(0)}, nil},
+		{largeHeader, []jwt.VerifyOption{jwt.MaxTokenSize(0), jwt.MaxHeaderSize(1)}, jwt.ErrTokenTooLarge},
+		{small, []jwt.VerifyOption{jwt.MaxTokenSize(16)}, jwt.ErrTokenTooLarge},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			var (
+				pl       testPayload
+				resolved bool
+			)
+			rv := &jwtutil.Resolver{New: func(jwt.Header) (jwt.Algorithm, error) {
+				resolved = true
+				return alg, nil
+			}}
+			hd, err := jwt.Verify(tc.token, rv, &pl, tc.opts...)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if tc.err == nil {
+				return
+			}
+			if !internal.ErrorIs(err, jwt.ErrMalformed) {
+				t.Errorf("jwt.Verify err = %v, want it to wrap %v", err, jwt.ErrMalformed)
+			}
+			// The limits must be enforced before any part of the token is decoded.
+			if want, got := (jwt.Header{}), hd; !cmp.Equal(got, want) {
+				t.Errorf("jwt.Verify header mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if resolved {
+				t.Error("jwt.Verify resolved the algorithm of a token that is too large")
+			}
+		})
+	}
+	for _, token := range [][]byte{large, largeHeader} {
+		if _, err = jwt.Decode(token); !internal.ErrorIs(err, jwt.ErrTokenTooLarge) {
+			t.Errorf("jwt.Decode err = %v, want %v", err, jwt.ErrTokenTooLarge)
+		}
+	}
+}
+
+func TestCriticalParams(t *testing.T) {
+	testCases := []struct {
+		header string
+		opts   []jwt.VerifyOption
+		err    error
+	}{
+		{`{"alg":"none"}`, nil, nil},
+		{`{"alg":"none","crit":["exp"],"exp":1}`, nil, jwt.ErrCritValidation},
+		{`{"alg":"none","crit":["exp"],"exp":1}`, []jwt.VerifyOption{jwt.CriticalParams("exp")}, nil},
+		{`{"alg":"none","crit":["exp","foo"],"exp":1,"foo":2}`, []jwt.VerifyOption{jwt.CriticalParams("exp")}, jwt.ErrCritValidation},
+		{`{"alg":"none","crit":["exp","foo"],"exp":1,"foo":2}`, []jwt.VerifyOption{jwt.CriticalParams("exp", "foo")}, nil},
+		{`{"alg":"none","crit":[]}`, nil, jwt.ErrCritValidation},
+		{`{"alg":"none","crit":["kid"],"kid":"test"}`, []jwt.VerifyOption{jwt.CriticalParams("kid")}, jwt.ErrCritValidation},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			token := base64.RawURLEncoding.EncodeToString([]byte(tc.header)) + "." +
+				base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"someone"}`)) + "."
+			var pl jwt.Payload
+			_, err := jwt.Verify([]byte(token), jwt.None(), &pl, tc.opts...)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestValidateType(t *testing.T) {
+	testCases := []struct {
+		header string
+		types  []string
+		err    error
+	}{
+		{`{"alg":"none","typ":"JWT"}`, []string{"JWT"}, nil},
+		{`{"alg":"none","typ":"jwt"}`, []string{"JWT"}, nil},
+		{`{"alg":"none","typ":"at+jwt"}`, []string{"at+JWT"}, nil},
+		{`{"alg":"none","typ":"application/at+jwt"}`, []string{"at+jwt"}, nil},
+		{`{"alg":"none","typ":"at+jwt"}`, []string{"application/at+jwt"}, nil},
+		{`{"alg":"none","typ":"at+jwt"}`, []string{"JWT", "at+jwt"}, nil},
+		{`{"alg":"none","typ":"at+jwt"}`, []string{"JWT"}, jwt.ErrTypValidation},
+		{`{"alg":"none","typ":"JWT"}`, []string{"at+jwt"}, jwt.ErrTypValidation},
+		{`{"alg":"none"}`, []string{"JWT"}, jwt.ErrTypValidation},
+		{`{"alg":"none","typ":"JWT"}`, nil, jwt.ErrTypValidation},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			token := base64.RawURLEncoding.EncodeToString([]byte(tc.header)) + "." +
+				base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"someone"}`)) + "."
+			var pl jwt.Payload
+			_, err := jwt.Verify([]byte(token), jwt.None(), &pl, jwt.ValidateType(tc.types...))
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestValidateHeaderParams(t *testing.T) {
+	alg := jwt.NewHS256(hmacKey1)
+	requireKID := func(hd *jwt.Header) error {
+		if hd.KeyID == "" {
+			return testErr
+		}
+		return nil
+	}
+	testCases := []struct {
+		opts []jwt.SignOption
+		vds  []jwt.HeaderValidator
+		err  error
+	}{
+		{[]jwt.SignOption{jwt.KeyID("kid")}, []jwt.HeaderValidator{requireKID}, nil},
+		{nil, []jwt.HeaderValidator{requireKID}, testErr},
+		{nil, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			token, err := jwt.Sign(tp, alg, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pl testPayload
+			_, err = jwt.Verify(token, alg, &pl, jwt.ValidateHeaderParams(tc.vds...))
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestDisallowDuplicateKeys(t *testing.T) {
+	hs256 := jwt.NewHS256(hmacKey1)
+	segment := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	withSig := func(header, payload string) []byte {
+		headerPayload := segment(header) + "." + segment(payload)
+		sig, err := hs256.Sign([]byte(headerPayload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []byte(headerPayload + "." + base64.RawURLEncoding.EncodeToString(sig))
+	}
+	header := `{"alg":"HS256","typ":"JWT"}`
+	testCases := []struct {
+		name   string
+		token  []byte
+		strict bool
+		err    error
+	}{
+		{"unique keys", withSig(header, `{"iss":"a","aud":["b","c"],"x":{"iss":"a"}}`), true, nil},
+		{"lenient", withSig(header, `{"iss":"a","iss":"b"}`), false, nil},
+		{"payload", withSig(header, `{"iss":"a","iss":"b"}`), true, jwt.ErrDuplicateKey},
+		{"nested", withSig(header, `{"x":[{"a":1,"a":2}]}`), true, jwt.ErrDuplicateKey},
+		{"header", withSig(`{"alg":"none","alg":"HS256"}`, `{}`), true, jwt.ErrDuplicateKey},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []jwt.VerifyOption
+			if tc.strict {
+				opts = append(opts, jwt.DisallowDuplicateKeys())
+			}
+			var pl jwt.Payload
+			_, err := jwt.Verify(tc.token, hs256, &pl, opts...)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if tc.err != nil && !internal.ErrorIs(err, jwt.ErrMalformed) {
+				t.Errorf("jwt.Verify err %q is not jwt.ErrMalformed", err)
+			}
+		})
+	}
+}
+
 func TestValidatePayload(t *testing.T) {
 	now := time.Now()
 	testCases := []struct {
@@ -161,3 +747,167 @@ func TestValidatePayload(t *testing.T) {
 		})
 	}
 }
+
+func TestReportValidatorIndex(t *testing.T) {
+	hs256 := jwt.NewHS256(hmacKey1)
+	token, err := jwt.Sign(jwt.Payload{Subject: "sub", Issuer: "iss"}, hs256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vds := []jwt.Validator{
+		jwt.SubjectValidator("sub"),
+		jwt.IssuerValidator("other"),
+		jwt.IDValidator("jti"),
+	}
+	t.Run("enabled", func(t *testing.T) {
+		var pl jwt.Payload
+		_, err := jwt.Verify(token, hs256, &pl, jwt.ValidatePayload(&pl, vds...), jwt.ReportValidatorIndex())
+		var verr *jwt.ValidatorError
+		if !internal.ErrorAs(err, &verr) {
+			t.Fatalf("jwt.Verify err %q is not a *jwt.ValidatorError", err)
+		}
+		if want, got := 1, verr.Index; got != want {
+			t.Errorf("jwt.ValidatorError.Index mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+		if want, got := jwt.ErrIssValidation, err; !internal.ErrorIs(got, want) {
+			t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+	t.Run("disabled", func(t *testing.T) {
+		var pl jwt.Payload
+		_, err := jwt.Verify(token, hs256, &pl, jwt.ValidatePayload(&pl, vds...))
+		var verr *jwt.ValidatorError
+		if internal.ErrorAs(err, &verr) {
+			t.Errorf("jwt.Verify err %q is a *jwt.ValidatorError", err)
+		}
+		if want, got := jwt.ErrIssValidation, err; !internal.ErrorIs(got, want) {
+			t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	})
+}
+
+func TestSignatureOnly(t *testing.T) {
+	alg := jwt.NewHS256(hmacKey1)
+	expired := jwt.Payload{Subject: "sub", ExpirationTime: jwt.NumericDate(time.Unix(0, 0))}
+	token, err := jwt.Sign(expired, alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name  string
+		token []byte
+		opts  func(*jwt.Payload) []jwt.VerifyOption
+		err   error
+	}{
+		{
+			"validators",
+			token,
+			func(pl *jwt.Payload) []jwt.VerifyOption {
+				return []jwt.VerifyOption{jwt.ValidatePayload(pl, jwt.ExpirationTimeValidator(time.Now()))}
+			},
+			jwt.ErrExpValidation,
+		},
+		{
+			"signature only",
+			token,
+			func(pl *jwt.Payload) []jwt.VerifyOption {
+				return []jwt.VerifyOption{jwt.SignatureOnly(), jwt.ValidatePayload(pl, jwt.ExpirationTimeValidator(time.Now()))}
+			},
+			nil,
+		},
+		{
+			"signature only with raw validators",
+			token,
+			func(*jwt.Payload) []jwt.VerifyOption {
+				return []jwt.VerifyOption{jwt.SignatureOnly(), jwt.ValidateRawPayload(jwt.SchemaValidator([]byte(`{"required":["iss"]}`)))}
+			},
+			nil,
+		},
+		{
+			"invalid signature",
+			append(token[:len(token):len(token)], 'A'),
+			func(*jwt.Payload) []jwt.VerifyOption { return []jwt.VerifyOption{jwt.SignatureOnly()} },
+			jwt.ErrSignatureMismatch,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pl jwt.Payload
+			_, err := jwt.Verify(tc.token, alg, &pl, tc.opts(&pl)...)
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Fatalf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+			if err == nil && !cmp.Equal(pl, expired) {
+				t.Errorf("jwt.Verify payload mismatch (-want +got):\n%s", cmp.Diff(expired, pl))
+			}
+		})
+	}
+}
+
+func TestVerifyPayloadOnValidationError(t *testing.T) {
+	alg := jwt.NewHS256(hmacKey1)
+	want := jwt.Payload{Issuer: "iss", Subject: "sub", ExpirationTime: jwt.NumericDate(time.Unix(0, 0))}
+	token, err := jwt.Sign(want, alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pl jwt.Payload
+	_, err = jwt.Verify(token, alg, &pl, jwt.ValidatePayload(&pl, jwt.ExpirationTimeValidator(time.Now())))
+	if want, got := jwt.ErrExpValidation, err; !internal.ErrorIs(got, want) {
+		t.Fatalf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if got := pl; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify payload mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	pl = jwt.Payload{}
+	_, err = jwt.Verify(token, jwt.NewHS256(hmacKey2), &pl)
+	if want, got := jwt.ErrSignatureMismatch, err; !internal.ErrorIs(got, want) {
+		t.Fatalf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if want, got := (jwt.Payload{}), pl; !cmp.Equal(got, want) {
+		t.Errorf("jwt.Verify decoded a payload with an invalid signature (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestAllowedHeaderParams(t *testing.T) {
+	alg := jwt.NewHS256(hmacKey1)
+	withHeader := func(header string) []byte {
+		headerPayload := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(`{}`))
+		sig, err := alg.Sign([]byte(headerPayload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []byte(headerPayload + "." + base64.RawURLEncoding.EncodeToString(sig))
+	}
+	testCases := []struct {
+		header string
+		params []string
+		err    error
+	}{
+		{`{"alg":"HS256","typ":"JWT","cty":"JWT","kid":"kid"}`, nil, nil},
+		{`{"alg":"HS256","jku":"https://example.com"}`, nil, jwt.ErrHeaderParamNotAllowed},
+		{`{"alg":"HS256","foo":"bar"}`, nil, jwt.ErrHeaderParamNotAllowed},
+		{`{"alg":"HS256","foo":"bar"}`, []string{"foo"}, nil},
+		{`{"alg":"HS256","x5c":[]}`, nil, jwt.ErrHeaderParamNotAllowed},
+		{`{"alg":"HS256","b64":true}`, nil, jwt.ErrHeaderParamNotAllowed},
+		{`{"alg":"HS256","x5t#S256":"foo","zip":"DEF"}`, []string{"x5t#S256", "zip"}, nil},
+		{`{"alg":"HS256","x5t#S256":"foo","zip":"DEF"}`, []string{"x5t#S256"}, jwt.ErrHeaderParamNotAllowed},
+		{`{"alg":"HS256","jku":""}`, nil, jwt.ErrHeaderParamNotAllowed},
+		{`{"alg":"HS256","jwk":null}`, nil, jwt.ErrHeaderParamNotAllowed},
+		{`{"alg":"HS256","x5c":null}`, nil, jwt.ErrHeaderParamNotAllowed},
+		{`{"alg":"HS256","crit":null}`, nil, jwt.ErrHeaderParamNotAllowed},
+		{`{"alg":"HS256","enc":""}`, nil, jwt.ErrHeaderParamNotAllowed},
+		{`{"alg":"HS256","typ":""}`, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			var pl jwt.Payload
+			_, err := jwt.Verify(withHeader(tc.header), alg, &pl, jwt.AllowedHeaderParams(tc.params...))
+			if want, got := tc.err, err; !internal.ErrorIs(got, want) {
+				t.Errorf("jwt.Verify err mismatch (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}